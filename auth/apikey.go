@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// apiKeyPrefix makes keys easy to recognise in logs and secret scanners
+const apiKeyPrefix = "blog_"
+
+// GenerateAPIKey returns a new random key, a short non-secret prefix for
+// identifying it in listings, and the hash that should be stored
+func GenerateAPIKey() (key, prefix, hash string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate api key: %v", err)
+	}
+
+	key = apiKeyPrefix + hex.EncodeToString(secret)
+	return key, key[:len(apiKeyPrefix)+8], HashAPIKey(key), nil
+}
+
+// HashAPIKey hashes a plaintext key for storage and lookup. Keys carry 256 bits
+// of entropy, so a fast hash is enough and lets us look keys up by hash.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
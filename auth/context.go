@@ -0,0 +1,37 @@
+package auth
+
+import "github.com/gin-gonic/gin"
+
+const principalKey = "auth.principal"
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	UserID uint
+	Role   string
+
+	// APIKeyID is set when the request was authenticated with an API key,
+	// in which case Scopes limits what the key may do on the user's behalf
+	APIKeyID *uint
+	Scopes   []string
+}
+
+// SetPrincipal stores the authenticated caller on the request context
+func SetPrincipal(c *gin.Context, p Principal) {
+	c.Set(principalKey, p)
+}
+
+// CurrentPrincipal returns the authenticated caller, if the request was authenticated
+func CurrentPrincipal(c *gin.Context) (Principal, bool) {
+	value, exists := c.Get(principalKey)
+	if !exists {
+		return Principal{}, false
+	}
+	p, ok := value.(Principal)
+	return p, ok
+}
+
+// UserID returns the authenticated user's ID, if the request was authenticated
+func UserID(c *gin.Context) (uint, bool) {
+	p, ok := CurrentPrincipal(c)
+	return p.UserID, ok
+}
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/susbuntu/blog-api/config"
+)
+
+// Token types carried in the "typ" claim so a refresh token can't be used as an access token
+const (
+	AccessToken  = "access"
+	RefreshToken = "refresh"
+)
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Claims are the JWT claims issued by the API
+type Claims struct {
+	UserID    uint   `json:"uid"`
+	TokenType string `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// TokenPair is an access/refresh token pair issued to a user
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
+
+// TokenManager signs and verifies HS256 JWTs
+type TokenManager struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+func NewTokenManager(cfg config.JWTConfig) *TokenManager {
+	return &TokenManager{
+		secret:     []byte(cfg.Secret),
+		accessTTL:  cfg.AccessTokenTTL,
+		refreshTTL: cfg.RefreshTokenTTL,
+	}
+}
+
+// Issue creates a new access/refresh token pair for the given user
+func (m *TokenManager) Issue(userID uint) (TokenPair, error) {
+	access, err := m.sign(userID, AccessToken, m.accessTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	refresh, err := m.sign(userID, RefreshToken, m.refreshTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		ExpiresIn:    m.accessTTL,
+	}, nil
+}
+
+// Parse verifies the signature, expiry and token type and returns the claims
+func (m *TokenManager) Parse(tokenString, tokenType string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return m.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	if claims.TokenType != tokenType {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+func (m *TokenManager) sign(userID uint, tokenType string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		UserID:    userID,
+		TokenType: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatUint(uint64(userID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign %s token: %v", tokenType, err)
+	}
+	return signed, nil
+}
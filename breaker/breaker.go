@@ -0,0 +1,128 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// State is where a breaker is in its cycle
+type State string
+
+const (
+	// Closed lets every call through
+	Closed State = "closed"
+	// Open rejects calls until the cooldown has passed
+	Open State = "open"
+	// HalfOpen lets a single trial call through to probe the dependency
+	HalfOpen State = "half_open"
+)
+
+// ErrOpen is returned instead of calling a dependency whose breaker is open
+var ErrOpen = errors.New("circuit breaker is open")
+
+// Breaker is a circuit breaker for one dependency. After Threshold failures in
+// a row it opens and rejects calls for Cooldown, so callers fall back straight
+// away instead of waiting on a dead dependency. Once the cooldown has passed a
+// single trial call is let through: success closes the breaker again, failure
+// reopens it.
+type Breaker struct {
+	Name      string
+	Threshold int
+	Cooldown  time.Duration
+
+	mu        sync.Mutex
+	state     State
+	failures  int
+	openedAt  time.Time
+	trial     bool
+	lastError string
+}
+
+// Status is a snapshot of a breaker for health output
+type Status struct {
+	Name      string     `json:"name" example:"elasticsearch"`
+	State     State      `json:"state" example:"open"`
+	Failures  int        `json:"failures" example:"5"`
+	OpenedAt  *time.Time `json:"opened_at,omitempty" example:"2023-09-14T08:04:38Z"`
+	LastError string     `json:"last_error,omitempty" example:"dial tcp 127.0.0.1:9200: connect: connection refused"`
+}
+
+func New(name string, threshold int, cooldown time.Duration) *Breaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &Breaker{Name: name, Threshold: threshold, Cooldown: cooldown, state: Closed}
+}
+
+// Do calls fn if the breaker allows it and records the outcome. It returns
+// ErrOpen without calling fn while the breaker is open. Cancellation by the
+// caller isn't held against the dependency.
+func (b *Breaker) Do(fn func() error) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+
+	err := fn()
+	b.record(err)
+	return err
+}
+
+// Status reports the breaker's current state
+func (b *Breaker) Status() Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := Status{Name: b.Name, State: b.state, Failures: b.failures, LastError: b.lastError}
+	if b.state != Closed {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+	return status
+}
+
+func (b *Breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case Open:
+		if time.Since(b.openedAt) < b.Cooldown {
+			return ErrOpen
+		}
+		b.state = HalfOpen
+		b.trial = true
+		return nil
+	case HalfOpen:
+		// Only one trial at a time; everyone else keeps falling back
+		if b.trial {
+			return ErrOpen
+		}
+		b.trial = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+	switch {
+	case errors.Is(err, context.Canceled):
+		// The caller gave up; that says nothing about the dependency
+	case err == nil:
+		b.state = Closed
+		b.failures = 0
+	default:
+		b.failures++
+		b.lastError = err.Error()
+		if b.state == HalfOpen || b.failures >= b.Threshold {
+			b.state = Open
+			b.openedAt = time.Now()
+		}
+	}
+}
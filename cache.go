@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/susbuntu/blog-api/cache"
+	"github.com/susbuntu/blog-api/config"
+	"github.com/susbuntu/blog-api/database"
+)
+
+// runCache handles the "cache" command
+func runCache(cfg *config.Config, args []string) error {
+	if len(args) != 1 || args[0] != "flush" {
+		return errors.New("usage: cache flush")
+	}
+
+	rdb := database.InitRedis(cfg)
+	ctx := context.Background()
+
+	deleted := 0
+	for _, pattern := range cache.Patterns {
+		// SCAN rather than KEYS so a large cache doesn't block Redis
+		iter := rdb.Scan(ctx, 0, pattern, 1000).Iterator()
+		var keys []string
+		for iter.Next(ctx) {
+			keys = append(keys, iter.Val())
+			if len(keys) == 1000 {
+				if err := rdb.Del(ctx, keys...).Err(); err != nil {
+					return err
+				}
+				deleted += len(keys)
+				keys = keys[:0]
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := rdb.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
+			deleted += len(keys)
+		}
+	}
+
+	log.Printf("Flushed %d cached keys", deleted)
+	return nil
+}
@@ -0,0 +1,21 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrMiss is returned by Get for keys that aren't cached
+var ErrMiss = errors.New("cache miss")
+
+// Cache holds values for a limited time. Failures are never fatal to callers:
+// everything cached can be rebuilt from the database.
+type Cache interface {
+	// Get returns the value stored under key, or ErrMiss
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores value under key for ttl
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes keys; missing keys are ignored
+	Delete(ctx context.Context, keys ...string) error
+}
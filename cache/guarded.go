@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/susbuntu/blog-api/breaker"
+)
+
+// Guarded puts a circuit breaker in front of a Cache. While the breaker is
+// open every Get is a miss and writes are dropped, so reads go straight to
+// the database instead of waiting on a cache that is down.
+//
+// Deletes that fail are remembered and retried before the next call that gets
+// through, so nothing is read back from the cache until the invalidations
+// missed during an outage have been applied. The pending keys are held in
+// memory by this process only and are lost on restart.
+type Guarded struct {
+	Cache   Cache
+	Breaker *breaker.Breaker
+
+	mu sync.Mutex
+	// pending is the set of keys whose delete failed; it can't grow past the
+	// number of distinct cache keys
+	pending map[string]struct{}
+}
+
+func NewGuarded(c Cache, b *breaker.Breaker) *Guarded {
+	return &Guarded{Cache: c, Breaker: b, pending: make(map[string]struct{})}
+}
+
+func (g *Guarded) Get(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	miss := false
+	err := g.Breaker.Do(func() error {
+		if err := g.flush(ctx); err != nil {
+			return err
+		}
+		var err error
+		value, err = g.Cache.Get(ctx, key)
+		// A miss means the cache is working
+		if errors.Is(err, ErrMiss) {
+			miss = true
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if miss {
+		return nil, ErrMiss
+	}
+	return value, nil
+}
+
+func (g *Guarded) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return g.Breaker.Do(func() error {
+		if err := g.flush(ctx); err != nil {
+			return err
+		}
+		return g.Cache.Set(ctx, key, value, ttl)
+	})
+}
+
+func (g *Guarded) Delete(ctx context.Context, keys ...string) error {
+	err := g.Breaker.Do(func() error {
+		if err := g.flush(ctx); err != nil {
+			return err
+		}
+		return g.Cache.Delete(ctx, keys...)
+	})
+	if err != nil {
+		g.mu.Lock()
+		for _, key := range keys {
+			g.pending[key] = struct{}{}
+		}
+		g.mu.Unlock()
+	}
+	return err
+}
+
+// flush retries the pending deletes. The lock is held across the call so a
+// key that fails again meanwhile isn't cleared by a delete that ran before it.
+func (g *Guarded) flush(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.pending) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(g.pending))
+	for key := range g.pending {
+		keys = append(keys, key)
+	}
+	if err := g.Cache.Delete(ctx, keys...); err != nil {
+		return err
+	}
+	g.pending = make(map[string]struct{})
+	return nil
+}
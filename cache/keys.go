@@ -0,0 +1,36 @@
+package cache
+
+import "fmt"
+
+// Patterns match every key the API writes, for flushing the cache without
+// touching anything else in the Redis database
+var Patterns = []string{"post:*", "feed:*"}
+
+// PostKey is the cache key a post is cached under
+func PostKey(id uint) string {
+	return fmt.Sprintf("post:%d", id)
+}
+
+// FeedKey is the cache key the site-wide feed in the given format ("rss" or "atom") is cached under
+func FeedKey(format string) string {
+	return "feed:" + format
+}
+
+// TagFeedKey is the cache key the Atom feed of a tag is cached under
+func TagFeedKey(tag string) string {
+	return "feed:tag:" + tag + ":atom"
+}
+
+// FeedKeys lists every cached feed a post with the given tags can appear in
+func FeedKeys(tags ...string) []string {
+	keys := []string{FeedKey("rss"), FeedKey("atom")}
+	for _, tag := range tags {
+		keys = append(keys, TagFeedKey(tag))
+	}
+	return keys
+}
+
+// PostSlugKey is the cache key holding the ID of the post a slug resolves to
+func PostSlugKey(slug string) string {
+	return "post:slug:" + slug
+}
@@ -2,13 +2,74 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	Port     string
-	Database DatabaseConfig
-	Redis    RedisConfig
-	ES       ElasticsearchConfig
+	Port        string
+	Backends    BackendsConfig
+	Breaker     BreakerConfig
+	Readiness   ReadinessConfig
+	Tracing     TracingConfig
+	Database    DatabaseConfig
+	Redis       RedisConfig
+	ES          ElasticsearchConfig
+	JWT         JWTConfig
+	Comments    CommentsConfig
+	Scheduler   SchedulerConfig
+	Outbox      OutboxConfig
+	Consistency ConsistencyConfig
+	Media       MediaConfig
+	Thumbnails  ThumbnailsConfig
+	Feeds       FeedsConfig
+
+	// SiteURL is the public base URL absolute links in feeds and sitemaps are built from
+	SiteURL string
+}
+
+// BackendsConfig picks the implementation behind each store. The memory
+// backends keep everything in process, for tests and single-instance local
+// runs; nothing survives a restart.
+type BackendsConfig struct {
+	// Storage is "postgres" or "memory". Memory storage only serves posts,
+	// search, accounts and the activity log; the RSS and Atom feeds and the
+	// sitemaps, including /feed.rss, /feed.atom and /sitemap.xml at the site
+	// root, are Postgres-only and 404.
+	Storage string
+	// Cache is "redis" or "memory"
+	Cache string
+	// Search is "elasticsearch" or "memory"
+	Search string
+}
+
+// BreakerConfig tunes the circuit breakers in front of Redis and Elasticsearch
+type BreakerConfig struct {
+	// Threshold is how many failures in a row open a breaker
+	Threshold int
+	// Cooldown is how long an open breaker waits before letting a trial call through
+	Cooldown time.Duration
+}
+
+type ReadinessConfig struct {
+	// Required names the dependencies ("postgres", "redis", "elasticsearch")
+	// whose failure makes /readyz answer 503; the others only degrade it
+	Required []string
+	// Timeout bounds each dependency check
+	Timeout time.Duration
+}
+
+type TracingConfig struct {
+	// Exporter is "none", "stdout" or "otlp". OTLP is sent over HTTP to the
+	// endpoint in the standard OTEL_EXPORTER_OTLP_* variables.
+	Exporter string
+	// File, when set, is where the stdout exporter writes instead of standard output
+	File        string
+	ServiceName string
+	// SampleRatio is the share of new traces recorded. Requests continuing a
+	// trace follow the caller's sampling decision.
+	SampleRatio float64
 }
 
 type DatabaseConfig struct {
@@ -17,6 +78,9 @@ type DatabaseConfig struct {
 	User     string
 	Password string
 	Name     string
+	// MigrateOnStart applies pending migrations when the server starts instead
+	// of refusing to run until "migrate up" has been run
+	MigrateOnStart bool
 }
 
 type RedisConfig struct {
@@ -29,15 +93,100 @@ type ElasticsearchConfig struct {
 	Port string
 }
 
+type JWTConfig struct {
+	Secret          string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+type CommentsConfig struct {
+	// Comments scoring at or above SpamThreshold go straight to spam
+	SpamThreshold float64
+	// Comments scoring below AutoApproveThreshold skip the moderation queue; 0 disables auto-approval
+	AutoApproveThreshold float64
+	MaxLinks             int
+	Blocklist            []string
+}
+
+type SchedulerConfig struct {
+	Enabled  bool
+	Interval time.Duration
+}
+
+type OutboxConfig struct {
+	Enabled  bool
+	Interval time.Duration
+	// MaxAttempts is how many times an event is tried before it is dead-lettered
+	MaxAttempts int
+	// Backoff is the delay after the first failure; it doubles with each further one up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+type ConsistencyConfig struct {
+	Enabled  bool
+	Interval time.Duration
+	// Repair queues fixes for the drift a scheduled check finds instead of only reporting it
+	Repair bool
+}
+
+type MediaConfig struct {
+	// Dir is where the local blob store keeps uploaded files
+	Dir           string
+	MaxUploadSize int64
+	// AllowedTypes lists the sniffed MIME types uploads may have
+	AllowedTypes []string
+}
+
+type ThumbnailsConfig struct {
+	Enabled  bool
+	Interval time.Duration
+	// Widths are the variant widths generated for each image; images are never upscaled
+	Widths []int
+	// Formats are the variant encodings, "jpeg" and/or "webp"
+	Formats     []string
+	JPEGQuality int
+	// MaxPixels is the largest width times height decoded; bigger images are marked failed
+	MaxPixels int
+}
+
+type FeedsConfig struct {
+	Title       string
+	Description string
+	// Size is how many of the latest posts a feed lists
+	Size int
+}
+
 func Load() *Config {
 	return &Config{
 		Port: getEnv("PORT", "8080"),
+		Backends: BackendsConfig{
+			Storage: getEnv("STORAGE_BACKEND", "postgres"),
+			Cache:   getEnv("CACHE_BACKEND", "redis"),
+			Search:  getEnv("SEARCH_BACKEND", "elasticsearch"),
+		},
+		Breaker: BreakerConfig{
+			Threshold: getEnvInt("BREAKER_FAILURE_THRESHOLD", 5),
+			Cooldown:  getEnvDuration("BREAKER_COOLDOWN", 30*time.Second),
+		},
+		Readiness: ReadinessConfig{
+			Required: getEnvList("READINESS_REQUIRED", []string{"postgres"}),
+			Timeout:  getEnvDuration("READINESS_TIMEOUT", 2*time.Second),
+		},
+		Tracing: TracingConfig{
+			Exporter:    getEnv("TRACING_EXPORTER", "none"),
+			File:        getEnv("TRACING_FILE", ""),
+			ServiceName: getEnv("TRACING_SERVICE_NAME", "blog-api"),
+			SampleRatio: getEnvFloat("TRACING_SAMPLE_RATIO", 1),
+		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     getEnv("DB_PORT", "5432"),
 			User:     getEnv("DB_USER", "blog_user"),
 			Password: getEnv("DB_PASSWORD", "blog_password"),
 			Name:     getEnv("DB_NAME", "blog_db"),
+
+			MigrateOnStart: getEnvBool("DB_MIGRATE_ON_START", false),
 		},
 		Redis: RedisConfig{
 			Host: getEnv("REDIS_HOST", "localhost"),
@@ -47,12 +196,132 @@ func Load() *Config {
 			Host: getEnv("ES_HOST", "localhost"),
 			Port: getEnv("ES_PORT", "9200"),
 		},
+		JWT: JWTConfig{
+			Secret:          getEnv("JWT_SECRET", DefaultJWTSecret),
+			AccessTokenTTL:  getEnvDuration("JWT_ACCESS_TTL", 15*time.Minute),
+			RefreshTokenTTL: getEnvDuration("JWT_REFRESH_TTL", 7*24*time.Hour),
+		},
+		Comments: CommentsConfig{
+			SpamThreshold:        getEnvFloat("COMMENT_SPAM_THRESHOLD", 0.7),
+			AutoApproveThreshold: getEnvFloat("COMMENT_AUTO_APPROVE_THRESHOLD", 0),
+			MaxLinks:             getEnvInt("COMMENT_MAX_LINKS", 2),
+			Blocklist:            getEnvList("COMMENT_BLOCKLIST", nil),
+		},
+		Scheduler: SchedulerConfig{
+			Enabled:  getEnvBool("SCHEDULER_ENABLED", true),
+			Interval: getEnvDuration("SCHEDULER_INTERVAL", 30*time.Second),
+		},
+		Outbox: OutboxConfig{
+			Enabled:     getEnvBool("OUTBOX_ENABLED", true),
+			Interval:    getEnvDuration("OUTBOX_INTERVAL", time.Second),
+			MaxAttempts: getEnvInt("OUTBOX_MAX_ATTEMPTS", 10),
+			Backoff:     getEnvDuration("OUTBOX_BACKOFF", 2*time.Second),
+			MaxBackoff:  getEnvDuration("OUTBOX_MAX_BACKOFF", 10*time.Minute),
+		},
+		Consistency: ConsistencyConfig{
+			Enabled:  getEnvBool("CONSISTENCY_CHECK_ENABLED", true),
+			Interval: getEnvDuration("CONSISTENCY_CHECK_INTERVAL", time.Hour),
+			Repair:   getEnvBool("CONSISTENCY_CHECK_REPAIR", false),
+		},
+		Media: MediaConfig{
+			Dir:           getEnv("MEDIA_DIR", "./uploads"),
+			MaxUploadSize: int64(getEnvInt("MEDIA_MAX_UPLOAD_SIZE", 10<<20)),
+			AllowedTypes:  getEnvList("MEDIA_ALLOWED_TYPES", []string{"image/jpeg", "image/png", "image/gif", "image/webp"}),
+		},
+		Thumbnails: ThumbnailsConfig{
+			Enabled:     getEnvBool("THUMBNAILS_ENABLED", true),
+			Interval:    getEnvDuration("THUMBNAILS_INTERVAL", 10*time.Second),
+			Widths:      getEnvIntList("THUMBNAIL_WIDTHS", []int{320, 640, 1280}),
+			Formats:     getEnvList("THUMBNAIL_FORMATS", []string{"jpeg", "webp"}),
+			JPEGQuality: getEnvInt("THUMBNAIL_JPEG_QUALITY", 80),
+			MaxPixels:   getEnvInt("THUMBNAIL_MAX_PIXELS", 25_000_000),
+		},
+		Feeds: FeedsConfig{
+			Title:       getEnv("FEED_TITLE", "Blog"),
+			Description: getEnv("FEED_DESCRIPTION", "Latest posts"),
+			Size:        getEnvInt("FEED_SIZE", 20),
+		},
+		SiteURL: strings.TrimRight(getEnv("SITE_URL", "http://localhost:8080"), "/"),
 	}
 }
 
+// DefaultJWTSecret is only meant for local development; production deployments must set JWT_SECRET
+const DefaultJWTSecret = "dev-secret-change-me"
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList reads a comma-separated list
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getEnvIntList reads a comma-separated list of integers, falling back to the
+// default if any item isn't a number
+func getEnvIntList(key string, defaultValue []int) []int {
+	items := getEnvList(key, nil)
+	if items == nil {
+		return defaultValue
+	}
+
+	values := make([]int, 0, len(items))
+	for _, item := range items {
+		i, err := strconv.Atoi(item)
+		if err != nil {
+			return defaultValue
+		}
+		values = append(values, i)
+	}
+	return values
+}
@@ -4,15 +4,22 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/olivere/elastic/v7"
 	"github.com/susbuntu/blog-api/config"
-	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/metrics"
+	"github.com/susbuntu/blog-api/search"
+	"github.com/susbuntu/blog-api/tracing"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// ensureIndexRetryInterval is how often creating the posts index is retried
+// while Elasticsearch is unreachable
+const ensureIndexRetryInterval = 5 * time.Second
+
 func InitPostgreSQL(cfg *config.Config) *gorm.DB {
 	dsn := fmt.Sprintf(
 		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=UTC",
@@ -23,39 +30,60 @@ func InitPostgreSQL(cfg *config.Config) *gorm.DB {
 		cfg.Database.Port,
 	)
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	// TranslateError turns unique violations into gorm.ErrDuplicatedKey so
+	// callers can tell a conflict from a failure
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		log.Fatal("Failed to connect to PostgreSQL:", err)
 	}
+	if err := metrics.InstrumentGORM(db); err != nil {
+		log.Fatal("Failed to instrument PostgreSQL queries:", err)
+	}
+	if err := tracing.InstrumentGORM(db); err != nil {
+		log.Fatal("Failed to instrument PostgreSQL queries:", err)
+	}
 
 	log.Println("Successfully connected to PostgreSQL")
 	return db
 }
 
+// InitRedis returns a Redis client. An unreachable server isn't fatal: the
+// cache is optional and the client reconnects once Redis is back.
 func InitRedis(cfg *config.Config) *redis.Client {
 	rdb := redis.NewClient(&redis.Options{
 		Addr: fmt.Sprintf("%s:%s", cfg.Redis.Host, cfg.Redis.Port),
 		DB:   0,
+		// Fail fast so a cache outage costs requests little time
+		DialTimeout:  time.Second,
+		ReadTimeout:  time.Second,
+		WriteTimeout: time.Second,
+		MaxRetries:   1,
 	})
+	rdb.AddHook(tracing.RedisHook{})
 
 	// Test connection
 	ctx := context.Background()
 	_, err := rdb.Ping(ctx).Result()
 	if err != nil {
-		log.Fatal("Failed to connect to Redis:", err)
+		log.Printf("WARNING: failed to connect to Redis, continuing without the cache until it is reachable: %v", err)
+		return rdb
 	}
 
 	log.Println("Successfully connected to Redis")
 	return rdb
 }
 
+// InitElasticsearch returns an Elasticsearch client. An unreachable cluster
+// isn't fatal: search falls back to Postgres meanwhile, and the posts index is
+// created in the background once the cluster answers.
 func InitElasticsearch(cfg *config.Config) *elastic.Client {
-	url := fmt.Sprintf("http://%s:%s", cfg.ES.Host, cfg.ES.Port)
+	url := ElasticsearchURL(cfg)
 	
 	client, err := elastic.NewClient(
 		elastic.SetURL(url),
 		elastic.SetSniff(false),
 		elastic.SetHealthcheck(false),
+		elastic.SetHttpClient(tracing.HTTPClient("elasticsearch")),
 	)
 	if err != nil {
 		log.Fatal("Failed to connect to Elasticsearch:", err)
@@ -65,63 +93,36 @@ func InitElasticsearch(cfg *config.Config) *elastic.Client {
 	ctx := context.Background()
 	_, _, err = client.Ping(url).Do(ctx)
 	if err != nil {
-		log.Fatal("Failed to ping Elasticsearch:", err)
+		log.Printf("WARNING: failed to ping Elasticsearch, search falls back to Postgres until it is reachable: %v", err)
+		go ensurePostsIndex(client)
+		return client
 	}
 
 	log.Println("Successfully connected to Elasticsearch")
 	
-	// Create index if it doesn't exist
-	createPostsIndex(client)
+	// Create the posts index and alias if they don't exist
+	if err := search.EnsurePostsIndex(ctx, client); err != nil {
+		log.Printf("Error creating posts index: %v", err)
+		go ensurePostsIndex(client)
+	}
 	
 	return client
 }
 
-func AutoMigrate(db *gorm.DB) {
-	err := db.AutoMigrate(&models.Post{}, &models.ActivityLog{})
-	if err != nil {
-		log.Fatal("Failed to migrate database:", err)
-	}
-	log.Println("Database migration completed")
+// ElasticsearchURL is the address of the configured cluster
+func ElasticsearchURL(cfg *config.Config) string {
+	return fmt.Sprintf("http://%s:%s", cfg.ES.Host, cfg.ES.Port)
 }
 
-func createPostsIndex(client *elastic.Client) {
-	ctx := context.Background()
-	
-	// Check if index exists
-	exists, err := client.IndexExists("posts").Do(ctx)
-	if err != nil {
-		log.Printf("Error checking if index exists: %v", err)
-		return
-	}
-	
-	if !exists {
-		// Create index with mapping
-		mapping := `{
-			"mappings": {
-				"properties": {
-					"id": {
-						"type": "integer"
-					},
-					"title": {
-						"type": "text",
-						"analyzer": "standard"
-					},
-					"content": {
-						"type": "text",
-						"analyzer": "standard"
-					},
-					"tags": {
-						"type": "keyword"
-					}
-				}
-			}
-		}`
-		
-		_, err := client.CreateIndex("posts").BodyString(mapping).Do(ctx)
-		if err != nil {
-			log.Printf("Error creating posts index: %v", err)
-		} else {
-			log.Println("Posts index created successfully")
+// ensurePostsIndex retries creating the posts index and alias until it
+// succeeds, so documents the outbox delivers once the cluster is back don't
+// land in an index created with dynamic mappings
+func ensurePostsIndex(client *elastic.Client) {
+	for {
+		time.Sleep(ensureIndexRetryInterval)
+		if err := search.EnsurePostsIndex(context.Background(), client); err == nil {
+			log.Println("Elasticsearch is reachable and the posts index is in place")
+			return
 		}
 	}
 }
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS activity_logs;
+DROP TABLE IF EXISTS post_media;
+DROP TABLE IF EXISTS media;
+DROP TABLE IF EXISTS comments;
+DROP TABLE IF EXISTS post_revisions;
+DROP TABLE IF EXISTS post_slugs;
+DROP TABLE IF EXISTS posts;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,141 @@
+-- Baseline schema. Tables and indexes are only created if missing. Databases
+-- set up by the first release through init.sql and GORM's AutoMigrate only
+-- have posts and activity_logs, so the columns added since then are added to
+-- those two tables before anything indexes them; 0002 then fixes up where
+-- init.sql and AutoMigrate disagreed.
+
+CREATE TABLE IF NOT EXISTS users (
+    id BIGSERIAL PRIMARY KEY,
+    email TEXT NOT NULL,
+    name TEXT,
+    role TEXT NOT NULL DEFAULT 'author',
+    password_hash TEXT NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    key_hash TEXT NOT NULL,
+    scopes TEXT[],
+    user_id BIGINT NOT NULL,
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_key_hash ON api_keys (key_hash);
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys (user_id);
+
+CREATE TABLE IF NOT EXISTS posts (
+    id BIGSERIAL PRIMARY KEY,
+    title TEXT NOT NULL,
+    slug TEXT,
+    content TEXT NOT NULL,
+    content_format TEXT NOT NULL DEFAULT 'markdown',
+    tags TEXT[] DEFAULT '{}',
+    author_id BIGINT,
+    status TEXT NOT NULL DEFAULT 'published',
+    published_at TIMESTAMPTZ,
+    scheduled_for TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS slug TEXT,
+    ADD COLUMN IF NOT EXISTS content_format TEXT NOT NULL DEFAULT 'markdown',
+    ADD COLUMN IF NOT EXISTS author_id BIGINT,
+    -- Posts from before statuses existed were all live
+    ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'published',
+    ADD COLUMN IF NOT EXISTS published_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS scheduled_for TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_posts_slug ON posts (slug);
+CREATE INDEX IF NOT EXISTS idx_posts_author_id ON posts (author_id);
+CREATE INDEX IF NOT EXISTS idx_posts_status ON posts (status);
+CREATE INDEX IF NOT EXISTS idx_posts_scheduled_for ON posts (scheduled_for);
+CREATE INDEX IF NOT EXISTS idx_posts_deleted_at ON posts (deleted_at);
+-- GIN index for tag containment queries (tags @> ARRAY[...])
+CREATE INDEX IF NOT EXISTS idx_posts_tags ON posts USING GIN (tags);
+CREATE INDEX IF NOT EXISTS idx_posts_created_at ON posts (created_at DESC);
+
+CREATE TABLE IF NOT EXISTS post_slugs (
+    id BIGSERIAL PRIMARY KEY,
+    slug TEXT NOT NULL,
+    post_id BIGINT NOT NULL,
+    created_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_post_slugs_slug ON post_slugs (slug);
+CREATE INDEX IF NOT EXISTS idx_post_slugs_post_id ON post_slugs (post_id);
+
+CREATE TABLE IF NOT EXISTS post_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    post_id BIGINT NOT NULL,
+    revision BIGINT NOT NULL,
+    title TEXT NOT NULL,
+    content TEXT NOT NULL,
+    content_format TEXT NOT NULL DEFAULT 'markdown',
+    tags TEXT[],
+    editor_id BIGINT,
+    restored_from BIGINT,
+    created_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_post_revisions_post_revision ON post_revisions (post_id, revision);
+
+CREATE TABLE IF NOT EXISTS comments (
+    id BIGSERIAL PRIMARY KEY,
+    post_id BIGINT NOT NULL,
+    parent_id BIGINT,
+    author_id BIGINT NOT NULL,
+    body TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    spam_score NUMERIC NOT NULL DEFAULT 0,
+    spam_reasons TEXT[],
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_comments_post_id ON comments (post_id);
+CREATE INDEX IF NOT EXISTS idx_comments_parent_id ON comments (parent_id);
+CREATE INDEX IF NOT EXISTS idx_comments_author_id ON comments (author_id);
+CREATE INDEX IF NOT EXISTS idx_comments_status ON comments (status);
+
+CREATE TABLE IF NOT EXISTS media (
+    id BIGSERIAL PRIMARY KEY,
+    owner_id BIGINT NOT NULL,
+    filename TEXT,
+    mime_type TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    checksum TEXT NOT NULL,
+    storage_key TEXT NOT NULL,
+    width BIGINT,
+    height BIGINT,
+    thumbnails TEXT NOT NULL DEFAULT 'pending',
+    variants JSONB,
+    created_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_media_owner_id ON media (owner_id);
+CREATE INDEX IF NOT EXISTS idx_media_checksum ON media (checksum);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_media_storage_key ON media (storage_key);
+CREATE INDEX IF NOT EXISTS idx_media_thumbnails ON media (thumbnails);
+
+CREATE TABLE IF NOT EXISTS post_media (
+    post_id BIGINT NOT NULL CONSTRAINT fk_posts_media REFERENCES posts (id),
+    media_id BIGINT NOT NULL CONSTRAINT fk_post_media_media REFERENCES media (id),
+    role TEXT NOT NULL,
+    created_at TIMESTAMPTZ,
+    PRIMARY KEY (post_id, media_id)
+);
+CREATE INDEX IF NOT EXISTS idx_post_media_media_id ON post_media (media_id);
+
+CREATE TABLE IF NOT EXISTS activity_logs (
+    id BIGSERIAL PRIMARY KEY,
+    action TEXT NOT NULL,
+    post_id BIGINT CONSTRAINT fk_activity_logs_post REFERENCES posts (id),
+    comment_id BIGINT,
+    logged_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+ALTER TABLE activity_logs ADD COLUMN IF NOT EXISTS comment_id BIGINT;
@@ -0,0 +1,3 @@
+-- Nothing to undo: going back to VARCHAR(255) titles and timestamps without
+-- time zone would only reintroduce the drift 0002 removed.
+SELECT 1;
@@ -0,0 +1,24 @@
+-- init.sql created posts and activity_logs with VARCHAR(255) titles, 32-bit
+-- IDs and timestamps without time zone, while the models expect unbounded
+-- text, 64-bit IDs and timestamptz. Each change is a no-op on databases that
+-- already match.
+
+-- Legacy timestamps were written in UTC
+SET LOCAL TIME ZONE 'UTC';
+
+ALTER TABLE posts
+    ALTER COLUMN id TYPE BIGINT,
+    ALTER COLUMN title TYPE TEXT,
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ;
+ALTER SEQUENCE IF EXISTS posts_id_seq AS BIGINT;
+
+ALTER TABLE activity_logs
+    ALTER COLUMN id TYPE BIGINT,
+    ALTER COLUMN action TYPE TEXT,
+    ALTER COLUMN post_id TYPE BIGINT,
+    ALTER COLUMN logged_at TYPE TIMESTAMPTZ;
+ALTER SEQUENCE IF EXISTS activity_logs_id_seq AS BIGINT;
+
+-- init.sql's foreign key duplicates the one GORM added next to it
+ALTER TABLE activity_logs DROP CONSTRAINT IF EXISTS activity_logs_post_id_fkey;
@@ -0,0 +1,17 @@
+-- Search index changes are written here in the same transaction as the post
+-- change and delivered to Elasticsearch by the outbox relay.
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    kind TEXT NOT NULL,
+    post_id BIGINT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts BIGINT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_post_id ON outbox_events (post_id);
+-- The relay polls for due pending events
+CREATE INDEX IF NOT EXISTS idx_outbox_events_due ON outbox_events (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_outbox_events_status ON outbox_events (status, id);
@@ -0,0 +1,16 @@
+-- Results of comparing the search index with the posts table
+CREATE TABLE IF NOT EXISTS consistency_checks (
+    id BIGSERIAL PRIMARY KEY,
+    started_at TIMESTAMPTZ NOT NULL,
+    duration BIGINT NOT NULL,
+    posts BIGINT NOT NULL,
+    documents BIGINT NOT NULL,
+    missing BIGINT NOT NULL,
+    stale BIGINT NOT NULL,
+    orphaned BIGINT NOT NULL,
+    missing_ids BIGINT[],
+    stale_ids BIGINT[],
+    orphaned_ids BIGINT[],
+    repaired BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ
+);
@@ -0,0 +1,4 @@
+-- Full-text index used when search falls back to Postgres. The expression
+-- must match search.Postgres exactly for the planner to use it.
+CREATE INDEX IF NOT EXISTS idx_posts_fulltext ON posts
+    USING GIN (to_tsvector('english', title || ' ' || content));
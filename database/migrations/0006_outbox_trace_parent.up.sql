@@ -0,0 +1,3 @@
+-- The W3C traceparent of the request that queued an event, so its delivery
+-- shows up in the same trace
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS trace_parent TEXT;
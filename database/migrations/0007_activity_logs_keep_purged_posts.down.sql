@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_activity_logs_post_id;
+-- Entries about purged posts can't satisfy the foreign key
+UPDATE activity_logs SET post_id = NULL
+    WHERE post_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM posts WHERE posts.id = activity_logs.post_id);
+ALTER TABLE activity_logs
+    ADD CONSTRAINT fk_activity_logs_post FOREIGN KEY (post_id) REFERENCES posts (id);
@@ -0,0 +1,5 @@
+-- Activity log entries keep the ID of a purged post so the audit trail still
+-- says which post it was about, so post_id can no longer reference posts
+ALTER TABLE activity_logs DROP CONSTRAINT IF EXISTS fk_activity_logs_post;
+ALTER TABLE activity_logs DROP CONSTRAINT IF EXISTS activity_logs_post_id_fkey;
+CREATE INDEX IF NOT EXISTS idx_activity_logs_post_id ON activity_logs (post_id);
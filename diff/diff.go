@@ -0,0 +1,229 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op is the kind of change a line represents
+type Op int
+
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+// Edit is one line of an edit script
+type Edit struct {
+	Op   Op
+	Line string
+}
+
+// MaxEdits caps the edit distance Lines will search for. The trace it keeps
+// grows with the square of the distance, so texts that differ by more than
+// this are only reported as different.
+const MaxEdits = 1000
+
+// MaxLines caps the combined length of the texts Lines will compare
+const MaxLines = 50000
+
+// Lines computes a shortest edit script turning a into b using Myers' algorithm.
+// It reports false when the texts are longer than MaxLines or differ by more
+// than MaxEdits lines.
+func Lines(a, b []string) ([]Edit, bool) {
+	// Lines shared at either end never need searching
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	middle, ok := shortestEdit(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+	if !ok {
+		return nil, false
+	}
+
+	edits := make([]Edit, 0, prefix+len(middle)+suffix)
+	for _, line := range a[:prefix] {
+		edits = append(edits, Edit{Op: Equal, Line: line})
+	}
+	edits = append(edits, middle...)
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, Edit{Op: Equal, Line: line})
+	}
+	return edits, true
+}
+
+func shortestEdit(a, b []string) ([]Edit, bool) {
+	n, m := len(a), len(b)
+	if n+m > MaxLines {
+		return nil, false
+	}
+	max := n + m
+	if max > MaxEdits {
+		max = MaxEdits
+	}
+	offset := max + 1
+	v := make([]int, 2*max+3)
+
+	// trace[d] holds the furthest-reaching x on diagonals -d-1 to d+1 before
+	// round d; no other diagonal is looked at when walking back from round d
+	var trace [][]int
+	at := func(d, k int) int { return trace[d][k+d+1] }
+
+	found := false
+search:
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break search
+			}
+		}
+	}
+	if !found {
+		return nil, false
+	}
+
+	// Walk the trace backwards to recover the path, then reverse it
+	var edits []Edit
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && at(d, k-1) < at(d, k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(d, prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			edits = append(edits, Edit{Op: Equal, Line: a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, Edit{Op: Insert, Line: b[y-1]})
+			} else {
+				edits = append(edits, Edit{Op: Delete, Line: a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits, true
+}
+
+// Unified renders the differences between two texts in unified diff format with
+// the given number of context lines. It returns an empty string when they match,
+// and only a one-line summary when they are too large or too different to diff.
+func Unified(fromName, toName, a, b string, context int) string {
+	edits, ok := Lines(splitLines(a), splitLines(b))
+	if !ok {
+		return fmt.Sprintf("Files %s and %s differ\n", fromName, toName)
+	}
+
+	// Line numbers (0-based) in a and b before each edit
+	aLine := make([]int, len(edits)+1)
+	bLine := make([]int, len(edits)+1)
+	for i, e := range edits {
+		aLine[i+1], bLine[i+1] = aLine[i], bLine[i]
+		if e.Op != Insert {
+			aLine[i+1]++
+		}
+		if e.Op != Delete {
+			bLine[i+1]++
+		}
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(edits); {
+		if edits[i].Op == Equal {
+			i++
+			continue
+		}
+
+		// Grow the hunk while the next change is within two context windows
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for j := i; j < len(edits); j++ {
+			if edits[j].Op != Equal {
+				end = j + 1
+			} else if j-end >= 2*context {
+				break
+			}
+		}
+		stop := end + context
+		if stop > len(edits) {
+			stop = len(edits)
+		}
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n",
+			hunkRange(aLine[start], aLine[stop]-aLine[start]),
+			hunkRange(bLine[start], bLine[stop]-bLine[start]))
+		for _, e := range edits[start:stop] {
+			switch e.Op {
+			case Equal:
+				out.WriteString(" ")
+			case Delete:
+				out.WriteString("-")
+			case Insert:
+				out.WriteString("+")
+			}
+			out.WriteString(e.Line)
+			out.WriteString("\n")
+		}
+
+		i = stop
+	}
+
+	return out.String()
+}
+
+// hunkRange formats a hunk header range. Empty ranges point at the line before them.
+func hunkRange(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/susbuntu/blog-api/auth"
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/policy"
+	"gorm.io/gorm"
+)
+
+// CreateAPIKey handles POST /api-keys - Creates a scoped API key for the current user
+// @Summary Create an API key
+// @Description Creates an API key that acts on behalf of the current user, limited to the given scopes. The plaintext key is only returned once.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param key body models.CreateAPIKeyRequest true "API key creation request"
+// @Security BearerAuth
+// @Success 201 {object} models.CreateAPIKeyResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api-keys [post]
+func (h *Handler) CreateAPIKey(c *gin.Context) {
+	if !authorize(c, policy.ManageAPIKeys, nil) {
+		return
+	}
+
+	var req models.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.ExpiresAt != nil && req.ExpiresAt.Before(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be in the future"})
+		return
+	}
+
+	key, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate API key"})
+		return
+	}
+
+	userID, _ := auth.UserID(c)
+	apiKey := models.APIKey{
+		Name:      req.Name,
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scopes:    models.StringArray(req.Scopes),
+		UserID:    userID,
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	if err := h.db(c).Create(&apiKey).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API key"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.CreateAPIKeyResponse{
+		APIKey: apiKey,
+		Key:    key,
+	})
+}
+
+// GetAPIKeys handles GET /api-keys - Lists the current user's API keys
+// @Summary List API keys
+// @Description Lists the current user's API keys, including revoked and expired ones. Admins see every key.
+// @Tags api-keys
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.APIKey
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api-keys [get]
+func (h *Handler) GetAPIKeys(c *gin.Context) {
+	if !authorize(c, policy.ManageAPIKeys, nil) {
+		return
+	}
+
+	var keys []models.APIKey
+	if err := h.scopeAPIKeys(c).Order("created_at DESC").Find(&keys).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch API keys"})
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// RevokeAPIKey handles DELETE /api-keys/:id - Revokes an API key
+// @Summary Revoke an API key
+// @Description Revokes an API key so it can no longer authenticate. The key is kept for auditing.
+// @Tags api-keys
+// @Produce json
+// @Param id path int true "API key ID"
+// @Security BearerAuth
+// @Success 200 {object} models.APIKey
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api-keys/{id} [delete]
+func (h *Handler) RevokeAPIKey(c *gin.Context) {
+	if !authorize(c, policy.ManageAPIKeys, nil) {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key ID"})
+		return
+	}
+
+	var apiKey models.APIKey
+	if err := h.scopeAPIKeys(c).First(&apiKey, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+		return
+	}
+
+	if apiKey.RevokedAt == nil {
+		now := time.Now()
+		apiKey.RevokedAt = &now
+		if err := h.db(c).Model(&apiKey).Update("revoked_at", now).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke API key"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, apiKey)
+}
+
+// scopeAPIKeys limits API key queries to the caller's own keys unless they may manage everyone's
+func (h *Handler) scopeAPIKeys(c *gin.Context) *gorm.DB {
+	subject, _ := currentSubject(c)
+	if policy.Can(subject, policy.ManageAllAPIKeys, nil) {
+		return h.db(c)
+	}
+	return h.db(c).Where("user_id = ?", subject.UserID)
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/susbuntu/blog-api/auth"
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/repository"
+)
+
+// Register handles POST /auth/register - Creates a user account and issues tokens
+// @Summary Register a new user
+// @Description Creates a user account and returns an access/refresh token pair
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param user body models.RegisterRequest true "Registration request"
+// @Success 201 {object} models.AuthResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /auth/register [post]
+func (h *Handler) Register(c *gin.Context) {
+	var req models.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		return
+	}
+
+	user := models.User{
+		Email:        email,
+		Name:         req.Name,
+		PasswordHash: hash,
+		Role:         models.RoleAuthor,
+	}
+
+	if err := h.Users.Create(c.Request.Context(), &user); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Email is already registered"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+		return
+	}
+
+	h.respondWithTokens(c, http.StatusCreated, user)
+}
+
+// Login handles POST /auth/login - Exchanges credentials for tokens
+// @Summary Log in
+// @Description Verifies email and password and returns an access/refresh token pair
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body models.LoginRequest true "Login request"
+// @Success 200 {object} models.AuthResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /auth/login [post]
+func (h *Handler) Login(c *gin.Context) {
+	var req models.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.Users.FindByEmail(c.Request.Context(), strings.ToLower(strings.TrimSpace(req.Email)))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to look up user"})
+		return
+	}
+
+	// Same response for unknown email and wrong password so accounts can't be enumerated
+	if err != nil || !auth.CheckPassword(user.PasswordHash, req.Password) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		return
+	}
+
+	h.respondWithTokens(c, http.StatusOK, user)
+}
+
+// Refresh handles POST /auth/refresh - Exchanges a refresh token for a new token pair
+// @Summary Refresh tokens
+// @Description Validates a refresh token and returns a new access/refresh token pair
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param token body models.RefreshRequest true "Refresh request"
+// @Success 200 {object} models.AuthResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /auth/refresh [post]
+func (h *Handler) Refresh(c *gin.Context) {
+	var req models.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, err := h.Tokens.Parse(req.RefreshToken, auth.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		return
+	}
+
+	// Make sure the account still exists before issuing new tokens
+	user, err := h.Users.Find(c.Request.Context(), claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		return
+	}
+
+	h.respondWithTokens(c, http.StatusOK, user)
+}
+
+// Me handles GET /auth/me - Returns the authenticated user
+// @Summary Get current user
+// @Description Returns the user the access token was issued to
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.User
+// @Failure 401 {object} models.ErrorResponse
+// @Router /auth/me [get]
+func (h *Handler) Me(c *gin.Context) {
+	userID, _ := auth.UserID(c)
+
+	user, err := h.Users.Find(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User no longer exists"})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// respondWithTokens issues a token pair for the user and writes the auth response
+func (h *Handler) respondWithTokens(c *gin.Context, status int, user models.User) {
+	tokens, err := h.Tokens.Issue(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue tokens"})
+		return
+	}
+
+	c.JSON(status, models.AuthResponse{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(tokens.ExpiresIn.Seconds()),
+		User:         user,
+	})
+}
@@ -0,0 +1,389 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/susbuntu/blog-api/auth"
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/policy"
+	"gorm.io/gorm"
+)
+
+// threadQuery walks comment threads depth-first starting from the roots selected
+// by the first argument, returning each comment with its depth and a path that
+// sorts replies directly under their parent in creation order. The second
+// argument filters replies; replies under a filtered-out comment are skipped too.
+const threadQuery = `
+WITH RECURSIVE thread AS (
+	SELECT c.*, 0 AS depth, ARRAY[c.id] AS path
+	FROM comments c
+	WHERE %s
+	UNION ALL
+	SELECT c.*, t.depth + 1, t.path || c.id
+	FROM comments c
+	JOIN thread t ON c.parent_id = t.id
+	WHERE %s
+)
+SELECT * FROM thread ORDER BY path`
+
+// visibleReplies limits thread queries to comments readers may see
+const visibleReplies = "c.status = 'approved'"
+
+// allReplies includes replies in every moderation state
+const allReplies = "TRUE"
+
+// CreateComment handles POST /posts/:id/comments - Adds a comment or reply to a post
+// @Summary Comment on a post
+// @Description Adds a comment to a post. Set parent_id to reply to another comment on the same post. Comments from authors start pending moderation unless the spam scorer flags them as spam; comments from editors and admins are approved immediately.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param comment body models.CreateCommentRequest true "Comment creation request"
+// @Security BearerAuth
+// @Success 201 {object} models.Comment
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /posts/{id}/comments [post]
+func (h *Handler) CreateComment(c *gin.Context) {
+	postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	var req models.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !authorize(c, policy.CreateComment, nil) {
+		return
+	}
+
+	var post models.Post
+	if err := h.db(c).Select("id", "status", "author_id").First(&post, postID).Error; err != nil || !canReadPost(c, &post) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	// Replies must stay within the same post and can only answer visible comments
+	if req.ParentID != nil {
+		var parent models.Comment
+		if err := h.db(c).Where("post_id = ? AND status = ?", postID, models.CommentApproved).First(&parent, *req.ParentID).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parent comment not found on this post"})
+			return
+		}
+	}
+
+	userID, _ := auth.UserID(c)
+	comment := models.Comment{
+		PostID:   uint(postID),
+		ParentID: req.ParentID,
+		AuthorID: userID,
+		Body:     req.Body,
+	}
+	if err := h.moderate(c, &comment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to score comment"})
+		return
+	}
+
+	err = h.db(c).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&comment).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.ActivityLog{
+			Action:    "new_comment",
+			PostID:    &comment.PostID,
+			CommentID: &comment.ID,
+		}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// GetComments handles GET /posts/:id/comments - Lists a post's comments with pagination
+// @Summary List comments on a post
+// @Description Lists approved comments either as a tree (top-level comments with nested replies, paginated by top-level comment) or flat in thread order with a depth field
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param view query string false "Response shape" Enums(tree, flat) default(tree)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} models.CommentsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /posts/{id}/comments [get]
+func (h *Handler) GetComments(c *gin.Context) {
+	postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	view := c.DefaultQuery("view", "tree")
+	if view != "tree" && view != "flat" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "view must be tree or flat"})
+		return
+	}
+
+	// Parse pagination parameters
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	offset := (page - 1) * limit
+
+	var post models.Post
+	if err := h.db(c).Select("id", "status", "author_id").First(&post, postID).Error; err != nil || !canReadPost(c, &post) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	var (
+		comments []models.Comment
+		total    int64
+	)
+
+	if view == "flat" {
+		roots := "c.post_id = @post AND c.parent_id IS NULL AND " + visibleReplies
+		countQuery := "SELECT COUNT(*) FROM (" + fmt.Sprintf(threadQuery, roots, visibleReplies) + ") AS visible"
+		if err := h.db(c).Raw(countQuery, map[string]interface{}{"post": postID}).Scan(&total).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count comments"})
+			return
+		}
+
+		query := fmt.Sprintf(threadQuery, roots, visibleReplies) + " LIMIT @limit OFFSET @offset"
+		err := h.db(c).Raw(query, map[string]interface{}{
+			"post":   postID,
+			"limit":  limit,
+			"offset": offset,
+		}).Scan(&comments).Error
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+			return
+		}
+	} else {
+		roots := func() *gorm.DB {
+			return h.db(c).Model(&models.Comment{}).Where("post_id = ? AND parent_id IS NULL AND status = ?", postID, models.CommentApproved)
+		}
+		if err := roots().Count(&total).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count comments"})
+			return
+		}
+
+		var rootIDs []uint
+		if err := roots().Order("id ASC").Offset(offset).Limit(limit).Pluck("id", &rootIDs).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+			return
+		}
+
+		var thread []models.Comment
+		if len(rootIDs) > 0 {
+			if err := h.db(c).Raw(fmt.Sprintf(threadQuery, "c.id IN ?", visibleReplies), rootIDs).Scan(&thread).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+				return
+			}
+		}
+		comments = buildCommentTree(thread)
+	}
+
+	if comments == nil {
+		comments = []models.Comment{}
+	}
+
+	// Calculate pagination info
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments": comments,
+		"pagination": gin.H{
+			"current_page": page,
+			"total_pages":  totalPages,
+			"total_count":  total,
+			"limit":        limit,
+			"has_next":     page < totalPages,
+			"has_prev":     page > 1,
+		},
+	})
+}
+
+// UpdateComment handles PUT /posts/:id/comments/:comment_id - Edits a comment
+// @Summary Edit a comment
+// @Description Edits the body of a comment. Only the comment's author (or an admin) may edit it. Edits by untrusted users go back through moderation.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param comment_id path int true "Comment ID"
+// @Param comment body models.UpdateCommentRequest true "Comment update request"
+// @Security BearerAuth
+// @Success 200 {object} models.Comment
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /posts/{id}/comments/{comment_id} [put]
+func (h *Handler) UpdateComment(c *gin.Context) {
+	comment, ok := h.findComment(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !authorize(c, policy.EditComment, &comment) {
+		return
+	}
+
+	comment.Body = req.Body
+	if err := h.moderate(c, &comment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to score comment"})
+		return
+	}
+
+	err := h.db(c).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&comment).Select("body", "status", "spam_score", "spam_reasons").Updates(&comment).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(&models.ActivityLog{
+			Action:    "update_comment",
+			PostID:    &comment.PostID,
+			CommentID: &comment.ID,
+		}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// DeleteComment handles DELETE /posts/:id/comments/:comment_id - Deletes a comment and its replies
+// @Summary Delete a comment
+// @Description Deletes a comment together with all replies beneath it
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param comment_id path int true "Comment ID"
+// @Security BearerAuth
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /posts/{id}/comments/{comment_id} [delete]
+func (h *Handler) DeleteComment(c *gin.Context) {
+	comment, ok := h.findComment(c)
+	if !ok {
+		return
+	}
+
+	if !authorize(c, policy.DeleteComment, &comment) {
+		return
+	}
+
+	var deleted int64
+	err := h.db(c).Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`DELETE FROM comments WHERE id IN (SELECT id FROM (`+fmt.Sprintf(threadQuery, "c.id = ?", allReplies)+`) AS subtree)`, comment.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+
+		return tx.Create(&models.ActivityLog{
+			Action:    "delete_comment",
+			PostID:    &comment.PostID,
+			CommentID: &comment.ID,
+		}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Comment deleted successfully",
+		"id":      comment.ID,
+		"deleted": deleted,
+	})
+}
+
+// findComment loads the comment addressed by the :id and :comment_id path
+// parameters, writing an error response and returning false if it doesn't exist
+func (h *Handler) findComment(c *gin.Context) (models.Comment, bool) {
+	postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return models.Comment{}, false
+	}
+
+	commentID, err := strconv.ParseUint(c.Param("comment_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return models.Comment{}, false
+	}
+
+	var comment models.Comment
+	if err := h.db(c).Where("post_id = ?", postID).First(&comment, commentID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return models.Comment{}, false
+	}
+
+	return comment, true
+}
+
+// buildCommentTree nests comments returned in thread order under their parents
+func buildCommentTree(thread []models.Comment) []models.Comment {
+	children := make(map[uint][]models.Comment)
+	var roots []models.Comment
+	for _, comment := range thread {
+		if comment.ParentID == nil || comment.Depth == 0 {
+			roots = append(roots, comment)
+		} else {
+			children[*comment.ParentID] = append(children[*comment.ParentID], comment)
+		}
+	}
+
+	var attach func(comment *models.Comment)
+	attach = func(comment *models.Comment) {
+		comment.Replies = children[comment.ID]
+		for i := range comment.Replies {
+			attach(&comment.Replies[i])
+		}
+	}
+	for i := range roots {
+		attach(&roots[i])
+	}
+
+	return roots
+}
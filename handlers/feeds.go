@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/susbuntu/blog-api/cache"
+	"github.com/susbuntu/blog-api/feed"
+	"github.com/susbuntu/blog-api/models"
+	"gorm.io/gorm"
+)
+
+// feedCacheTTL bounds how long a rendered feed is kept. Post writes invalidate
+// feeds directly, so this only catches changes they don't see, such as an
+// author changing their name.
+const feedCacheTTL = time.Hour
+
+const (
+	rssContentType  = "application/rss+xml; charset=utf-8"
+	atomContentType = "application/atom+xml; charset=utf-8"
+)
+
+// cachedFeed is a rendered feed along with the validators sent with it
+type cachedFeed struct {
+	Body         string    `json:"body"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// GetRSSFeed handles GET /feed.rss - RSS 2.0 feed of the latest posts
+// @Summary RSS feed
+// @Description Lists the latest published posts as RSS 2.0. Supports conditional requests through ETag and Last-Modified. Also served at /feed.rss on the site root.
+// @Tags feeds
+// @Produce xml
+// @Success 200 {string} string "RSS document"
+// @Success 304 {string} string "Not modified"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /feed.rss [get]
+func (h *Handler) GetRSSFeed(c *gin.Context) {
+	h.serveFeed(c, cache.FeedKey("rss"), rssContentType, func() (*cachedFeed, error) {
+		f, err := h.buildFeed(h.db(c), h.Config.Feeds.Title, h.Config.SiteURL+"/feed.rss", h.Config.SiteURL+"/api/v1/posts")
+		if err != nil {
+			return nil, err
+		}
+		return renderFeed(f, feed.RSS)
+	})
+}
+
+// GetAtomFeed handles GET /feed.atom - Atom feed of the latest posts
+// @Summary Atom feed
+// @Description Lists the latest published posts as Atom 1.0. Supports conditional requests through ETag and Last-Modified. Also served at /feed.atom on the site root.
+// @Tags feeds
+// @Produce xml
+// @Success 200 {string} string "Atom document"
+// @Success 304 {string} string "Not modified"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /feed.atom [get]
+func (h *Handler) GetAtomFeed(c *gin.Context) {
+	h.serveFeed(c, cache.FeedKey("atom"), atomContentType, func() (*cachedFeed, error) {
+		f, err := h.buildFeed(h.db(c), h.Config.Feeds.Title, h.Config.SiteURL+"/feed.atom", h.Config.SiteURL+"/api/v1/posts")
+		if err != nil {
+			return nil, err
+		}
+		return renderFeed(f, feed.Atom)
+	})
+}
+
+// GetTagAtomFeed handles GET /tags/:tag/feed.atom - Atom feed of a tag's latest posts
+// @Summary Tag Atom feed
+// @Description Lists the latest published posts with the given tag as Atom 1.0. Supports conditional requests through ETag and Last-Modified. Also served at /tags/{tag}/feed.atom on the site root.
+// @Tags feeds
+// @Produce xml
+// @Param tag path string true "Tag name"
+// @Success 200 {string} string "Atom document"
+// @Success 304 {string} string "Not modified"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tags/{tag}/feed.atom [get]
+func (h *Handler) GetTagAtomFeed(c *gin.Context) {
+	tag := c.Param("tag")
+
+	h.serveFeed(c, cache.TagFeedKey(tag), atomContentType, func() (*cachedFeed, error) {
+		title := fmt.Sprintf("%s: %s", h.Config.Feeds.Title, tag)
+		self := fmt.Sprintf("%s/tags/%s/feed.atom", h.Config.SiteURL, url.PathEscape(tag))
+		f, err := h.buildFeed(h.db(c).Where("tags @> ARRAY[?]", tag), title, self, h.tagLink(tag))
+		if err != nil {
+			return nil, err
+		}
+		return renderFeed(f, feed.Atom)
+	})
+}
+
+// serveFeed answers from the cache, building and caching the feed on a
+// miss. http.ServeContent takes care of If-None-Match and If-Modified-Since.
+func (h *Handler) serveFeed(c *gin.Context, cacheKey, contentType string, build func() (*cachedFeed, error)) {
+	ctx := c.Request.Context()
+
+	var cached cachedFeed
+	cachedData, err := h.Cache.Get(ctx, cacheKey)
+	if err != nil || json.Unmarshal(cachedData, &cached) != nil {
+		built, err := build()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build feed"})
+			return
+		}
+		cached = *built
+
+		feedJSON, _ := json.Marshal(cached)
+		h.Cache.Set(ctx, cacheKey, feedJSON, feedCacheTTL)
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("ETag", cached.ETag)
+	http.ServeContent(c.Writer, c.Request, "", cached.LastModified, strings.NewReader(cached.Body))
+}
+
+// buildFeed loads the latest published posts matching query, newest first
+func (h *Handler) buildFeed(query *gorm.DB, title, self, link string) (*feed.Feed, error) {
+	var posts []models.Post
+	err := query.Where("status = ?", models.PostPublished).
+		Order("COALESCE(published_at, created_at) DESC").
+		Limit(h.Config.Feeds.Size).
+		Find(&posts).Error
+	if err != nil {
+		return nil, err
+	}
+	renderContents(posts)
+
+	authors, err := h.authorNames(query.Statement.Context, posts)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &feed.Feed{
+		Title:       title,
+		Description: h.Config.Feeds.Description,
+		Link:        link,
+		Self:        self,
+	}
+	for _, post := range posts {
+		published := post.CreatedAt
+		if post.PublishedAt != nil {
+			published = *post.PublishedAt
+		}
+		if post.UpdatedAt.After(f.Updated) {
+			f.Updated = post.UpdatedAt
+		}
+
+		entry := feed.Entry{
+			ID:         fmt.Sprintf("%s/api/v1/posts/%d", h.Config.SiteURL, post.ID),
+			Title:      post.Title,
+			Link:       h.postLink(post.Slug),
+			Categories: post.Tags,
+			Content:    post.ContentHTML,
+			Published:  published,
+			Updated:    post.UpdatedAt,
+		}
+		if post.AuthorID != nil {
+			entry.Author = authors[*post.AuthorID]
+		}
+		f.Entries = append(f.Entries, entry)
+	}
+
+	return f, nil
+}
+
+// authorNames maps the authors of the given posts to their display names
+func (h *Handler) authorNames(ctx context.Context, posts []models.Post) (map[uint]string, error) {
+	var ids []uint
+	for _, post := range posts {
+		if post.AuthorID != nil {
+			ids = append(ids, *post.AuthorID)
+		}
+	}
+
+	names := make(map[uint]string)
+	if len(ids) == 0 {
+		return names, nil
+	}
+
+	var users []models.User
+	if err := h.DB.WithContext(ctx).Select("id", "name").Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		names[user.ID] = user.Name
+	}
+	return names, nil
+}
+
+// renderFeed serializes a feed and derives its validators from the entries'
+// UpdatedAt. The ETag covers every entry, so it also changes when a post drops
+// out of the feed without anything else being updated.
+func renderFeed(f *feed.Feed, format func(*feed.Feed) ([]byte, error)) (*cachedFeed, error) {
+	body, err := format(f)
+	if err != nil {
+		return nil, err
+	}
+
+	hasher := sha256.New()
+	for _, entry := range f.Entries {
+		fmt.Fprintf(hasher, "%s@%d\n", entry.ID, entry.Updated.UnixNano())
+	}
+
+	return &cachedFeed{
+		Body:         string(body),
+		ETag:         `"` + hex.EncodeToString(hasher.Sum(nil))[:32] + `"`,
+		LastModified: f.Updated,
+	}, nil
+}
+
+// postLink is the absolute URL readers are sent to for a post
+func (h *Handler) postLink(postSlug string) string {
+	return h.Config.SiteURL + "/api/v1/posts/by-slug/" + url.PathEscape(postSlug)
+}
+
+// tagLink is the absolute URL of a tag's landing page
+func (h *Handler) tagLink(tag string) string {
+	return h.Config.SiteURL + "/api/v1/posts/search-by-tag?tag=" + url.QueryEscape(tag)
+}
+
+// invalidateFeeds drops the cached feeds a post with any of the given tags may appear in
+func (h *Handler) invalidateFeeds(ctx context.Context, tags ...string) {
+	h.Cache.Delete(ctx, cache.FeedKeys(tags...)...)
+}
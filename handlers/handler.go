@@ -1,21 +1,83 @@
 package handlers
 
 import (
-	"github.com/go-redis/redis/v8"
+	"context"
+
+	"github.com/gin-gonic/gin"
 	"github.com/olivere/elastic/v7"
+	"github.com/susbuntu/blog-api/auth"
+	"github.com/susbuntu/blog-api/breaker"
+	"github.com/susbuntu/blog-api/cache"
+	"github.com/susbuntu/blog-api/config"
+	"github.com/susbuntu/blog-api/health"
+	"github.com/susbuntu/blog-api/repository"
+	"github.com/susbuntu/blog-api/search"
+	"github.com/susbuntu/blog-api/spam"
+	"github.com/susbuntu/blog-api/storage"
 	"gorm.io/gorm"
 )
 
+// Stores are the backends handlers read and write through. The core post,
+// search and account endpoints only use the interfaces, so they also run on
+// the in-memory implementations; DB and ES are nil then, and the endpoints
+// that need them aren't registered.
+type Stores struct {
+	DB           *gorm.DB
+	ES           *elastic.Client
+	Cache        cache.Cache
+	Search       search.SearchIndex
+	Posts        repository.PostRepository
+	ActivityLogs repository.ActivityLogRepository
+	Users        repository.UserRepository
+	// Breakers guard the optional dependencies and are reported by /readyz
+	Breakers []*breaker.Breaker
+	// Checks probe each external dependency for /readyz
+	Checks []health.Check
+}
+
 type Handler struct {
-	DB    *gorm.DB
-	Redis *redis.Client
-	ES    *elastic.Client
+	Config       *config.Config
+	DB           *gorm.DB
+	ES           *elastic.Client
+	Cache        cache.Cache
+	Search       search.SearchIndex
+	Posts        repository.PostRepository
+	ActivityLogs repository.ActivityLogRepository
+	Users        repository.UserRepository
+	Breakers     []*breaker.Breaker
+	Checks       []health.Check
+	Tokens       *auth.TokenManager
+	Spam         spam.Scorer
+	Blobs        storage.BlobStore
 }
 
-func NewHandler(db *gorm.DB, redis *redis.Client, es *elastic.Client) *Handler {
+func NewHandler(cfg *config.Config, stores Stores, tokens *auth.TokenManager) *Handler {
 	return &Handler{
-		DB:    db,
-		Redis: redis,
-		ES:    es,
+		Config:       cfg,
+		DB:           stores.DB,
+		ES:           stores.ES,
+		Cache:        stores.Cache,
+		Search:       stores.Search,
+		Posts:        stores.Posts,
+		ActivityLogs: stores.ActivityLogs,
+		Users:        stores.Users,
+		Breakers:     stores.Breakers,
+		Checks:       stores.Checks,
+		Tokens:       tokens,
+		Spam:         spam.NewHeuristic(cfg.Comments.MaxLinks, cfg.Comments.Blocklist),
+		Blobs:        storage.NewLocalStore(cfg.Media.Dir),
 	}
 }
+
+// db scopes queries to the request, so they are traced under it and stop when
+// the client goes away
+func (h *Handler) db(c *gin.Context) *gorm.DB {
+	return h.DB.WithContext(c.Request.Context())
+}
+
+// detached keeps the request's trace but not its cancellation, for work that
+// has to finish once a change is committed, such as cache invalidation, or
+// that outlives the request
+func detached(c *gin.Context) context.Context {
+	return context.WithoutCancel(c.Request.Context())
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/susbuntu/blog-api/health"
+)
+
+// Livez handles GET /livez - Reports that the process is up
+// @Summary Liveness probe
+// @Description Answers 200 whenever the process can serve requests. Dependencies aren't checked, so an outage elsewhere doesn't get healthy replicas restarted. Also served at /livez on the site root.
+// @Tags health
+// @Produce json
+// @Success 200 {object} object{status=string}
+// @Router /livez [get]
+func (h *Handler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz handles GET /readyz - Reports whether the API can serve traffic
+// @Summary Readiness probe
+// @Description Checks Postgres, Redis and Elasticsearch at once, each with a timeout, and reports every dependency's status, latency and version along with the circuit breaker states. Answers 503 when a dependency listed in READINESS_REQUIRED is down. Optional dependencies that are down, or behind an open breaker, only make the status degraded, since the API serves without them. Also served at /readyz on the site root.
+// @Tags health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /readyz [get]
+func (h *Handler) Readyz(c *gin.Context) {
+	report := health.Run(c.Request.Context(), h.Checks, h.Config.Readiness.Required, h.Config.Readiness.Timeout, h.Breakers)
+
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
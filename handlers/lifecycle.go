@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/susbuntu/blog-api/cache"
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/policy"
+	"github.com/susbuntu/blog-api/repository"
+)
+
+// PublishPost handles POST /posts/:id/publish - Makes a draft or archived post live
+// @Summary Publish a post
+// @Description Moves a draft or archived post to published. published_at is set the first time a post goes live.
+// @Tags posts
+// @Produce json
+// @Param id path int true "Post ID"
+// @Security BearerAuth
+// @Success 200 {object} models.Post
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /posts/{id}/publish [post]
+func (h *Handler) PublishPost(c *gin.Context) {
+	h.transitionPost(c, models.PostPublished, "publish_post", models.PostDraft, models.PostArchived)
+}
+
+// UnpublishPost handles POST /posts/:id/unpublish - Takes a published post back to draft
+// @Summary Unpublish a post
+// @Description Moves a published post back to draft so it is hidden from readers
+// @Tags posts
+// @Produce json
+// @Param id path int true "Post ID"
+// @Security BearerAuth
+// @Success 200 {object} models.Post
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /posts/{id}/unpublish [post]
+func (h *Handler) UnpublishPost(c *gin.Context) {
+	h.transitionPost(c, models.PostDraft, "unpublish_post", models.PostPublished)
+}
+
+// ArchivePost handles POST /posts/:id/archive - Retires a published post
+// @Summary Archive a post
+// @Description Moves a published post to archived so it is hidden from readers but keeps its publication date
+// @Tags posts
+// @Produce json
+// @Param id path int true "Post ID"
+// @Security BearerAuth
+// @Success 200 {object} models.Post
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /posts/{id}/archive [post]
+func (h *Handler) ArchivePost(c *gin.Context) {
+	h.transitionPost(c, models.PostArchived, "archive_post", models.PostPublished)
+}
+
+// SchedulePost handles POST /posts/:id/schedule - Queues a draft to go live at a future time
+// @Summary Schedule a post
+// @Description Schedules a draft to be published by the background scheduler once scheduled_for has passed. Scheduling again replaces the previous time.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param schedule body models.SchedulePostRequest true "Publication time"
+// @Security BearerAuth
+// @Success 200 {object} models.Post
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /posts/{id}/schedule [post]
+func (h *Handler) SchedulePost(c *gin.Context) {
+	var req models.SchedulePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !req.ScheduledFor.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "scheduled_for must be in the future"})
+		return
+	}
+
+	h.setSchedule(c, &req.ScheduledFor, "schedule_post")
+}
+
+// UnschedulePost handles DELETE /posts/:id/schedule - Cancels a scheduled publication
+// @Summary Cancel a scheduled publication
+// @Description Clears scheduled_for so the draft stays unpublished
+// @Tags posts
+// @Produce json
+// @Param id path int true "Post ID"
+// @Security BearerAuth
+// @Success 200 {object} models.Post
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /posts/{id}/schedule [delete]
+func (h *Handler) UnschedulePost(c *gin.Context) {
+	h.setSchedule(c, nil, "unschedule_post")
+}
+
+// setSchedule sets or clears a draft's scheduled_for, logging the change along with it
+func (h *Handler) setSchedule(c *gin.Context, scheduledFor *time.Time, logAction string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	post, err := h.Posts.Update(c.Request.Context(), uint(id), repository.PostEdit{
+		Check: func(post *models.Post) error {
+			if !authorize(c, policy.PublishPost, post) {
+				return errResponded
+			}
+			if post.Status != models.PostDraft {
+				c.JSON(http.StatusConflict, gin.H{"error": "Only drafts can be scheduled"})
+				return errResponded
+			}
+			return nil
+		},
+		Apply: func(post *models.Post) {
+			post.ScheduledFor = scheduledFor
+		},
+		LogAction: logAction,
+	})
+	if err != nil {
+		respondPostError(c, err, "Failed to update post schedule")
+		return
+	}
+
+	// Invalidate cache
+	h.Cache.Delete(detached(c), cache.PostKey(post.ID))
+
+	renderContent(&post)
+	c.JSON(http.StatusOK, post)
+}
+
+// transitionPost moves a post to a new lifecycle state if it is currently in one
+// of the allowed states, logging the change along with it
+func (h *Handler) transitionPost(c *gin.Context, to, logAction string, from ...string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	post, err := h.Posts.Update(c.Request.Context(), uint(id), repository.PostEdit{
+		Check: func(post *models.Post) error {
+			if !authorize(c, policy.PublishPost, post) {
+				return errResponded
+			}
+
+			for _, status := range from {
+				if post.Status == status {
+					return nil
+				}
+			}
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Cannot move a %s post to %s", post.Status, to)})
+			return errResponded
+		},
+		Apply: func(post *models.Post) {
+			// A manual transition overrides any pending schedule
+			post.Status = to
+			post.ScheduledFor = nil
+			switch to {
+			case models.PostPublished:
+				if post.PublishedAt == nil {
+					now := time.Now()
+					post.PublishedAt = &now
+				}
+			case models.PostDraft:
+				post.PublishedAt = nil
+			}
+		},
+		LogAction: logAction,
+	})
+	if err != nil {
+		respondPostError(c, err, "Failed to update post status")
+		return
+	}
+
+	// Invalidate cache
+	ctx := detached(c)
+	h.Cache.Delete(ctx, cache.PostKey(post.ID))
+	h.invalidateFeeds(ctx, post.Tags...)
+
+	renderContent(&post)
+	c.JSON(http.StatusOK, post)
+}
+
+// canReadPost reports whether the caller may see the post. Published posts are
+// public; anything else is limited to callers allowed to read unpublished posts.
+func canReadPost(c *gin.Context, post *models.Post) bool {
+	if post.Status == models.PostPublished {
+		return true
+	}
+	subject, ok := currentSubject(c)
+	return ok && policy.Can(subject, policy.ReadUnpublished, post)
+}
+
+// postVisibility describes what the caller may read: everything for editors
+// and admins, published posts plus their own for authors, and published posts
+// only for anonymous readers.
+func postVisibility(c *gin.Context) models.PostVisibility {
+	subject, ok := currentSubject(c)
+	if !ok {
+		return models.PostVisibility{}
+	}
+	if policy.Can(subject, policy.ReadUnpublished, nil) {
+		return models.PostVisibility{All: true}
+	}
+
+	own := &models.Post{AuthorID: &subject.UserID}
+	if policy.Can(subject, policy.ReadUnpublished, own) {
+		return models.PostVisibility{AuthorID: &subject.UserID}
+	}
+	return models.PostVisibility{}
+}
@@ -0,0 +1,611 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/susbuntu/blog-api/auth"
+	"github.com/susbuntu/blog-api/cache"
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/policy"
+	"github.com/susbuntu/blog-api/storage"
+	"github.com/susbuntu/blog-api/thumbnail"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// multipartOverhead is headroom on top of the upload size limit for the
+// multipart boundaries and headers around the file
+const multipartOverhead = 1 << 20
+
+// sniffLength is how much of an upload http.DetectContentType looks at
+const sniffLength = 512
+
+// UploadMedia handles POST /media - Uploads a file
+// @Summary Upload media
+// @Description Uploads a file as multipart form field "file". The MIME type is sniffed from the content and must be in MEDIA_ALLOWED_TYPES; files larger than MEDIA_MAX_UPLOAD_SIZE are rejected.
+// @Tags media
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "File to upload"
+// @Security BearerAuth
+// @Success 201 {object} models.Media
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 415 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /media [post]
+func (h *Handler) UploadMedia(c *gin.Context) {
+	if !authorize(c, policy.UploadMedia, nil) {
+		return
+	}
+	userID, _ := auth.UserID(c)
+
+	maxSize := h.Config.Media.MaxUploadSize
+	tooLarge := fmt.Sprintf("File exceeds the %d byte upload limit", maxSize)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartOverhead)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": tooLarge})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A file is required in the \"file\" form field"})
+		return
+	}
+	if header.Size > maxSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": tooLarge})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read upload"})
+		return
+	}
+	defer file.Close()
+
+	// Sniff the type from the first bytes instead of trusting the client's Content-Type
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read upload"})
+		return
+	}
+	head = head[:n]
+
+	mimeType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if !h.mediaTypeAllowed(mimeType) {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": fmt.Sprintf("Files of type %s are not allowed", mimeType)})
+		return
+	}
+
+	key, err := newStorageKey()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store file"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	hasher := sha256.New()
+	content := io.TeeReader(io.MultiReader(bytes.NewReader(head), file), hasher)
+	if err := h.Blobs.Put(ctx, key, content); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store file"})
+		return
+	}
+
+	media := models.Media{
+		OwnerID:    userID,
+		Filename:   filepath.Base(header.Filename),
+		MIMEType:   mimeType,
+		Size:       header.Size,
+		Checksum:   hex.EncodeToString(hasher.Sum(nil)),
+		StorageKey: key,
+	}
+	if err := h.db(c).Create(&media).Error; err != nil {
+		h.deleteBlob(detached(c), key)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save media"})
+		return
+	}
+
+	decorateMedia(&media)
+	c.JSON(http.StatusCreated, media)
+}
+
+// GetMediaList handles GET /media - Lists uploaded media
+// @Summary List media
+// @Description Lists uploaded media with pagination, newest first. Admins see all uploads; everyone else sees their own.
+// @Tags media
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Security BearerAuth
+// @Success 200 {object} models.MediaListResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /media [get]
+func (h *Handler) GetMediaList(c *gin.Context) {
+	// Parse pagination parameters
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	offset := (page - 1) * limit
+
+	scoped := func() *gorm.DB {
+		query := h.db(c).Model(&models.Media{})
+		if subject, _ := currentSubject(c); !policy.Can(subject, policy.ListAllMedia, nil) {
+			query = query.Where("owner_id = ?", subject.UserID)
+		}
+		return query
+	}
+
+	var media []models.Media
+	var total int64
+
+	if err := scoped().Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count media"})
+		return
+	}
+
+	if err := scoped().Order("created_at DESC").Offset(offset).Limit(limit).Find(&media).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch media"})
+		return
+	}
+	for i := range media {
+		decorateMedia(&media[i])
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	c.JSON(http.StatusOK, gin.H{
+		"media": media,
+		"pagination": gin.H{
+			"current_page": page,
+			"total_pages":  totalPages,
+			"total_count":  total,
+			"limit":        limit,
+			"has_next":     page < totalPages,
+			"has_prev":     page > 1,
+		},
+	})
+}
+
+// GetMedia handles GET /media/:id - Gets media metadata
+// @Summary Get media metadata
+// @Description Retrieves the metadata of an uploaded file
+// @Tags media
+// @Produce json
+// @Param id path int true "Media ID"
+// @Success 200 {object} models.Media
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /media/{id} [get]
+func (h *Handler) GetMedia(c *gin.Context) {
+	media, ok := h.findMedia(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	decorateMedia(&media)
+	c.JSON(http.StatusOK, media)
+}
+
+// GetMediaContent handles GET /media/:id/content - Serves an uploaded file
+// @Summary Download media
+// @Description Streams the file with its sniffed content type. Content never changes for a given ID, so responses are cacheable indefinitely.
+// @Tags media
+// @Produce octet-stream
+// @Param id path int true "Media ID"
+// @Success 200 {file} binary
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /media/{id}/content [get]
+func (h *Handler) GetMediaContent(c *gin.Context) {
+	media, ok := h.findMedia(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	h.serveBlob(c, media.StorageKey, media.MIMEType, media.Size, media.Filename, media.Checksum)
+}
+
+// serveBlob streams a stored file. Blobs never change once written, so
+// responses may be cached indefinitely.
+func (h *Handler) serveBlob(c *gin.Context, key, mimeType string, size int64, filename, etag string) {
+	content, err := h.Blobs.Open(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Media content not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read media"})
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, size, mimeType, content, map[string]string{
+		"Cache-Control":          "public, max-age=31536000, immutable",
+		"Content-Disposition":    mime.FormatMediaType("inline", map[string]string{"filename": filename}),
+		"ETag":                   `"` + etag + `"`,
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// GetMediaVariant handles GET /media/:id/variants/:name - Serves a resized variant
+// @Summary Download a media variant
+// @Description Streams one of the resized variants listed in the media's variants, such as 640w.webp
+// @Tags media
+// @Produce octet-stream
+// @Param id path int true "Media ID"
+// @Param name path string true "Variant name"
+// @Success 200 {file} binary
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /media/{id}/variants/{name} [get]
+func (h *Handler) GetMediaVariant(c *gin.Context) {
+	media, ok := h.findMedia(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	name := c.Param("name")
+	for _, variant := range media.Variants {
+		if variant.Name == name {
+			h.serveBlob(c, thumbnail.VariantKey(media.StorageKey, name), variant.MIMEType, variant.Size, name, media.Checksum+"-"+name)
+			return
+		}
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": "Variant not found"})
+}
+
+// DeleteMedia handles DELETE /media/:id - Deletes an uploaded file
+// @Summary Delete media
+// @Description Deletes an upload and detaches it from any posts. Uploaders may delete their own files, admins any file.
+// @Tags media
+// @Produce json
+// @Param id path int true "Media ID"
+// @Security BearerAuth
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /media/{id} [delete]
+func (h *Handler) DeleteMedia(c *gin.Context) {
+	media, ok := h.findMedia(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	if !authorize(c, policy.DeleteMedia, &media) {
+		return
+	}
+
+	h.invalidatePostsUsingMedia(detached(c), media.ID)
+	err := h.db(c).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("media_id = ?", media.ID).Delete(&models.PostMedia{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&media).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete media"})
+		return
+	}
+
+	// The row is gone, so a leftover blob is only wasted space
+	ctx := detached(c)
+	h.deleteBlob(ctx, media.StorageKey)
+	for _, variant := range media.Variants {
+		h.deleteBlob(ctx, thumbnail.VariantKey(media.StorageKey, variant.Name))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Media deleted successfully",
+		"id":      media.ID,
+	})
+}
+
+// GetPostMedia handles GET /posts/:id/media - Lists a post's attached media
+// @Summary List a post's media
+// @Description Lists the cover image and inline assets attached to a post
+// @Tags media
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {array} models.PostMedia
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /posts/{id}/media [get]
+func (h *Handler) GetPostMedia(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	var post models.Post
+	if err := h.db(c).First(&post, id).Error; err != nil || !canReadPost(c, &post) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	var attachments []models.PostMedia
+	if err := h.db(c).Preload("Media").Where("post_id = ?", post.ID).Order("role ASC, created_at ASC").Find(&attachments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch media"})
+		return
+	}
+	for i := range attachments {
+		decorateMedia(&attachments[i].Media)
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+// AttachMedia handles POST /posts/:id/media - Attaches media to a post
+// @Summary Attach media to a post
+// @Description Attaches an upload to a post as its cover image or an inline asset. Attaching a cover replaces the previous one; attaching media that is already on the post changes its role.
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param attachment body models.AttachMediaRequest true "Media to attach"
+// @Security BearerAuth
+// @Success 200 {object} models.PostMedia
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /posts/{id}/media [post]
+func (h *Handler) AttachMedia(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	var req models.AttachMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Role == "" {
+		req.Role = models.MediaInline
+	}
+
+	// Start transaction
+	tx := h.db(c).Begin()
+	if tx.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+
+	// Lock the post so two covers can't be attached at once
+	var post models.Post
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&post, id).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	if !authorize(c, policy.EditPost, &post) {
+		tx.Rollback()
+		return
+	}
+
+	var media models.Media
+	if err := tx.First(&media, req.MediaID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+		return
+	}
+
+	if !authorize(c, policy.AttachMedia, &media) {
+		tx.Rollback()
+		return
+	}
+
+	if req.Role == models.MediaCover {
+		if err := tx.Where("post_id = ? AND role = ?", post.ID, models.MediaCover).Delete(&models.PostMedia{}).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to replace cover"})
+			return
+		}
+	}
+
+	attachment := models.PostMedia{PostID: post.ID, MediaID: media.ID, Role: req.Role}
+	err = tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "post_id"}, {Name: "media_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role"}),
+	}).Create(&attachment).Error
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to attach media"})
+		return
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	// Invalidate cache
+	h.Cache.Delete(detached(c), cache.PostKey(post.ID))
+
+	decorateMedia(&media)
+	attachment.Media = media
+	c.JSON(http.StatusOK, attachment)
+}
+
+// DetachMedia handles DELETE /posts/:id/media/:media_id - Detaches media from a post
+// @Summary Detach media from a post
+// @Description Removes an attachment from a post. The upload itself is kept.
+// @Tags media
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param media_id path int true "Media ID"
+// @Security BearerAuth
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /posts/{id}/media/{media_id} [delete]
+func (h *Handler) DetachMedia(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	mediaID, err := strconv.ParseUint(c.Param("media_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid media ID"})
+		return
+	}
+
+	var post models.Post
+	if err := h.db(c).First(&post, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	if !authorize(c, policy.EditPost, &post) {
+		return
+	}
+
+	result := h.db(c).Where("post_id = ? AND media_id = ?", post.ID, mediaID).Delete(&models.PostMedia{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to detach media"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Media is not attached to this post"})
+		return
+	}
+
+	// Invalidate cache
+	h.Cache.Delete(detached(c), cache.PostKey(post.ID))
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Media detached successfully",
+		"id":      mediaID,
+	})
+}
+
+// findMedia loads media by ID, writing an error response and returning false if it can't
+func (h *Handler) findMedia(c *gin.Context, idParam string) (models.Media, bool) {
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid media ID"})
+		return models.Media{}, false
+	}
+
+	var media models.Media
+	if err := h.db(c).First(&media, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+		return models.Media{}, false
+	}
+	return media, true
+}
+
+// mediaTypeAllowed reports whether uploads of the sniffed MIME type are accepted
+func (h *Handler) mediaTypeAllowed(mimeType string) bool {
+	for _, allowed := range h.Config.Media.AllowedTypes {
+		if mimeType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteBlob removes a blob in the background, logging failures
+func (h *Handler) deleteBlob(ctx context.Context, key string) {
+	go func() {
+		if err := h.Blobs.Delete(ctx, key); err != nil {
+			log.Printf("Failed to delete blob %s: %v", key, err)
+		}
+	}()
+}
+
+// newStorageKey returns a random blob key, fanned out by its first byte so
+// no single directory grows too large
+func newStorageKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	name := hex.EncodeToString(b)
+	return "media/" + name[:2] + "/" + name, nil
+}
+
+// decorateMedia fills in the URLs the media and its variants are served from,
+// and builds a srcset per variant type
+func decorateMedia(media *models.Media) {
+	media.URL = fmt.Sprintf("/api/v1/media/%d/content", media.ID)
+	if len(media.Variants) == 0 {
+		return
+	}
+
+	media.Srcset = make(map[string]string)
+	for i := range media.Variants {
+		variant := &media.Variants[i]
+		variant.URL = fmt.Sprintf("/api/v1/media/%d/variants/%s", media.ID, variant.Name)
+
+		candidate := fmt.Sprintf("%s %dw", variant.URL, variant.Width)
+		if existing := media.Srcset[variant.MIMEType]; existing != "" {
+			candidate = existing + ", " + candidate
+		}
+		media.Srcset[variant.MIMEType] = candidate
+	}
+}
+
+// decoratePostMedia decorates the media attached to each post
+func decoratePostMedia(posts []models.Post) {
+	for i := range posts {
+		for j := range posts[i].Media {
+			decorateMedia(&posts[i].Media[j].Media)
+		}
+	}
+}
+
+// invalidatePostsUsingMedia drops the cached copies of posts that embed the media
+func (h *Handler) invalidatePostsUsingMedia(ctx context.Context, mediaID uint) {
+	var postIDs []uint
+	if err := h.DB.WithContext(ctx).Model(&models.PostMedia{}).Where("media_id = ?", mediaID).Pluck("post_id", &postIDs).Error; err != nil {
+		log.Printf("Failed to find posts using media %d: %v", mediaID, err)
+		return
+	}
+	for _, postID := range postIDs {
+		h.Cache.Delete(ctx, cache.PostKey(postID))
+	}
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/policy"
+	"github.com/susbuntu/blog-api/spam"
+	"gorm.io/gorm"
+)
+
+// recentCommentsForSpam is how many of an author's previous comments the spam scorer compares against
+const recentCommentsForSpam = 20
+
+// moderationActions maps bulk moderation actions to the resulting comment state
+var moderationActions = map[string]string{
+	"approve": models.CommentApproved,
+	"reject":  models.CommentRejected,
+	"spam":    models.CommentSpam,
+}
+
+// GetModerationQueue handles GET /comments/moderation - Lists comments awaiting moderation
+// @Summary List the moderation queue
+// @Description Lists comments in a moderation state, oldest first, with pagination. Editors and admins only.
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param status query string false "Moderation state" Enums(pending, approved, rejected, spam) default(pending)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Security BearerAuth
+// @Success 200 {object} models.CommentsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /comments/moderation [get]
+func (h *Handler) GetModerationQueue(c *gin.Context) {
+	if !authorize(c, policy.ModerateComments, nil) {
+		return
+	}
+
+	status := c.DefaultQuery("status", models.CommentPending)
+	switch status {
+	case models.CommentPending, models.CommentApproved, models.CommentRejected, models.CommentSpam:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be pending, approved, rejected or spam"})
+		return
+	}
+
+	// Parse pagination parameters
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	offset := (page - 1) * limit
+
+	var comments []models.Comment
+	var total int64
+
+	if err := h.db(c).Model(&models.Comment{}).Where("status = ?", status).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count comments"})
+		return
+	}
+
+	if err := h.db(c).Where("status = ?", status).Order("created_at ASC").Offset(offset).Limit(limit).Find(&comments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		return
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments": comments,
+		"pagination": gin.H{
+			"current_page": page,
+			"total_pages":  totalPages,
+			"total_count":  total,
+			"limit":        limit,
+			"has_next":     page < totalPages,
+			"has_prev":     page > 1,
+		},
+	})
+}
+
+// ModerateComments handles POST /comments/moderation - Approves, rejects or marks comments as spam in bulk
+// @Summary Moderate comments in bulk
+// @Description Applies one moderation decision to up to 100 comments. Unknown IDs are skipped. Editors and admins only.
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param decision body models.ModerateCommentsRequest true "Moderation decision"
+// @Security BearerAuth
+// @Success 200 {object} models.ModerateCommentsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /comments/moderation [post]
+func (h *Handler) ModerateComments(c *gin.Context) {
+	if !authorize(c, policy.ModerateComments, nil) {
+		return
+	}
+
+	var req models.ModerateCommentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	status := moderationActions[req.Action]
+
+	updated := []uint{}
+	err := h.db(c).Transaction(func(tx *gorm.DB) error {
+		var comments []models.Comment
+		if err := tx.Where("id IN ?", req.IDs).Find(&comments).Error; err != nil {
+			return err
+		}
+		if len(comments) == 0 {
+			return nil
+		}
+
+		for _, comment := range comments {
+			updated = append(updated, comment.ID)
+		}
+
+		if err := tx.Model(&models.Comment{}).Where("id IN ?", updated).Update("status", status).Error; err != nil {
+			return err
+		}
+
+		logs := make([]models.ActivityLog, 0, len(comments))
+		for i := range comments {
+			logs = append(logs, models.ActivityLog{
+				Action:    req.Action + "_comment",
+				PostID:    &comments[i].PostID,
+				CommentID: &comments[i].ID,
+			})
+		}
+		return tx.Create(&logs).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to moderate comments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  status,
+		"updated": updated,
+	})
+}
+
+// moderate scores a new or edited comment and sets its moderation state.
+// Trusted commenters skip the queue; everyone else starts pending unless the
+// score is low enough to auto-approve or high enough to go straight to spam.
+func (h *Handler) moderate(c *gin.Context, comment *models.Comment) error {
+	if subject, _ := currentSubject(c); policy.TrustedCommenter(subject) {
+		comment.Status = models.CommentApproved
+		comment.SpamScore = 0
+		comment.SpamReasons = nil
+		return nil
+	}
+
+	var recent []string
+	err := h.db(c).Model(&models.Comment{}).
+		Where("author_id = ? AND id <> ?", comment.AuthorID, comment.ID).
+		Order("created_at DESC").
+		Limit(recentCommentsForSpam).
+		Pluck("body", &recent).Error
+	if err != nil {
+		return err
+	}
+
+	result := h.Spam.Score(spam.Input{Body: comment.Body, RecentBodies: recent})
+	comment.SpamScore = result.Score
+	comment.SpamReasons = models.StringArray(result.Reasons)
+
+	switch {
+	case result.Score >= h.Config.Comments.SpamThreshold:
+		comment.Status = models.CommentSpam
+	case result.Score < h.Config.Comments.AutoApproveThreshold:
+		comment.Status = models.CommentApproved
+	default:
+		comment.Status = models.CommentPending
+	}
+	return nil
+}
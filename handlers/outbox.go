@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/outbox"
+	"github.com/susbuntu/blog-api/policy"
+)
+
+// GetOutboxEvents handles GET /admin/search/outbox - Lists undelivered search index events
+// @Summary List outbox events
+// @Description Lists search index events that haven't been delivered to Elasticsearch, oldest first. Defaults to dead-lettered events, which have used up their retries; use status=pending for events still being retried. Admin only.
+// @Tags search
+// @Produce json
+// @Param status query string false "Event status" Enums(dead, pending) default(dead)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Security BearerAuth
+// @Success 200 {object} models.OutboxEventsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/search/outbox [get]
+func (h *Handler) GetOutboxEvents(c *gin.Context) {
+	if !authorize(c, policy.ManageSearch, nil) {
+		return
+	}
+
+	status := c.DefaultQuery("status", models.OutboxDead)
+	if status != models.OutboxDead && status != models.OutboxPending {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be dead or pending"})
+		return
+	}
+
+	// Parse pagination parameters
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	offset := (page - 1) * limit
+
+	var events []models.OutboxEvent
+	var total int64
+
+	query := h.db(c).Model(&models.OutboxEvent{}).Where("status = ?", status)
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count outbox events"})
+		return
+	}
+
+	if err := h.db(c).Where("status = ?", status).Order("id ASC").Offset(offset).Limit(limit).Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch outbox events"})
+		return
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"pagination": gin.H{
+			"current_page": page,
+			"total_pages":  totalPages,
+			"total_count":  total,
+			"limit":        limit,
+			"has_next":     page < totalPages,
+			"has_prev":     page > 1,
+		},
+	})
+}
+
+// ReplayOutboxEvent handles POST /admin/search/outbox/:id/replay - Retries a dead-lettered event
+// @Summary Replay a dead-lettered outbox event
+// @Description Queues a dead-lettered search index event for delivery again with a fresh set of retries. Delivery syncs the post's current state. Admin only.
+// @Tags search
+// @Produce json
+// @Param id path int true "Outbox event ID"
+// @Security BearerAuth
+// @Success 202 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/search/outbox/{id}/replay [post]
+func (h *Handler) ReplayOutboxEvent(c *gin.Context) {
+	if !authorize(c, policy.ManageSearch, nil) {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid outbox event ID"})
+		return
+	}
+
+	replayed, err := outbox.Replay(h.db(c), uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to replay outbox event"})
+		return
+	}
+	if !replayed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dead-lettered outbox event not found"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Outbox event queued for delivery"})
+}
+
+// ReplayOutboxEvents handles POST /admin/search/outbox/replay - Retries every dead-lettered event
+// @Summary Replay all dead-lettered outbox events
+// @Description Queues every dead-lettered search index event for delivery again, for example once Elasticsearch is back after an outage. Admin only.
+// @Tags search
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} models.ReplayOutboxResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/search/outbox/replay [post]
+func (h *Handler) ReplayOutboxEvents(c *gin.Context) {
+	if !authorize(c, policy.ManageSearch, nil) {
+		return
+	}
+
+	replayed, err := outbox.ReplayAll(h.db(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to replay outbox events"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"replayed": replayed})
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/susbuntu/blog-api/auth"
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/policy"
+)
+
+// authorize consults the policy layer for the current caller and writes a 403
+// response when the action is denied. Handlers should return when it reports false.
+func authorize(c *gin.Context, action policy.Action, resource policy.Resource) bool {
+	subject, ok := currentSubject(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Authentication required"})
+		return false
+	}
+
+	if !policy.Can(subject, action, resource) {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "You do not have permission to perform this action"})
+		return false
+	}
+
+	return true
+}
+
+// currentSubject describes the authenticated caller to the policy layer
+func currentSubject(c *gin.Context) (policy.Subject, bool) {
+	principal, ok := auth.CurrentPrincipal(c)
+	if !ok {
+		return policy.Subject{}, false
+	}
+
+	return policy.Subject{
+		UserID:    principal.UserID,
+		Role:      principal.Role,
+		ViaAPIKey: principal.APIKeyID != nil,
+		Scopes:    principal.Scopes,
+	}, true
+}
@@ -3,25 +3,35 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/olivere/elastic/v7"
+	"github.com/susbuntu/blog-api/auth"
+	"github.com/susbuntu/blog-api/cache"
+	"github.com/susbuntu/blog-api/metrics"
 	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/policy"
+	"github.com/susbuntu/blog-api/render"
+	"github.com/susbuntu/blog-api/repository"
 )
 
 // CreatePost handles POST /posts - Creates a new post with transaction support
 // @Summary Create a new blog post
-// @Description Create a new blog post with transaction support for data integrity
+// @Description Create a new blog post with transaction support for data integrity. New posts start as drafts; use the publish endpoint to make them live.
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Param post body models.CreatePostRequest true "Post creation request"
+// @Security BearerAuth
 // @Success 201 {object} models.Post
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /posts [post]
 func (h *Handler) CreatePost(c *gin.Context) {
@@ -31,53 +41,39 @@ func (h *Handler) CreatePost(c *gin.Context) {
 		return
 	}
 
-	// Start transaction
-	tx := h.DB.Begin()
-	if tx.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+	if !authorize(c, policy.CreatePost, nil) {
 		return
 	}
+	userID, _ := auth.UserID(c)
 
-	// Create post
 	post := models.Post{
-		Title:   req.Title,
-		Content: req.Content,
-		Tags:    models.StringArray(req.Tags),
+		Title:         req.Title,
+		Content:       req.Content,
+		ContentFormat: req.ContentFormat,
+		Tags:          models.StringArray(req.Tags),
+		AuthorID:      &userID,
+		Status:        models.PostDraft,
 	}
-
-	if err := tx.Create(&post).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create post"})
-		return
-	}
-
-	// Create activity log
-	activityLog := models.ActivityLog{
-		Action: "new_post",
-		PostID: &post.ID,
-	}
-
-	if err := tx.Create(&activityLog).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create activity log"})
-		return
+	if post.ContentFormat == "" {
+		post.ContentFormat = render.FormatMarkdown
 	}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+	// The repository stores the post with its slug, first revision, activity
+	// log entry and search indexing in one go
+	if err := h.Posts.Create(c.Request.Context(), &post); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create post"})
 		return
 	}
 
-	// Index in Elasticsearch
-	go h.indexPostInES(post)
+	h.invalidateFeeds(detached(c), post.Tags...)
 
+	renderContent(&post)
 	c.JSON(http.StatusCreated, post)
 }
 
 // GetPost handles GET /posts/:id - Gets a post with Cache-Aside pattern
 // @Summary Get a specific blog post
-// @Description Retrieves a post by ID with Redis caching (5-minute TTL)
+// @Description Retrieves a post by ID with Redis caching (5-minute TTL). Unpublished posts are only visible to their author, editors and admins.
 // @Tags posts
 // @Accept json
 // @Produce json
@@ -94,37 +90,107 @@ func (h *Handler) GetPost(c *gin.Context) {
 		return
 	}
 
-	ctx := context.Background()
-	cacheKey := fmt.Sprintf("post:%d", id)
+	post, err := h.findCachedPost(c.Request.Context(), uint(id))
+	if err != nil || !canReadPost(c, &post) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
 
-	// Try to get from Redis first (Cache-Aside pattern)
-	cachedData, err := h.Redis.Get(ctx, cacheKey).Result()
-	if err == nil {
-		// Cache hit - return cached data
-		var post models.Post
-		if json.Unmarshal([]byte(cachedData), &post) == nil {
-			c.JSON(http.StatusOK, post)
-			return
-		}
+	c.JSON(http.StatusOK, post)
+}
+
+// GetPostBySlug handles GET /posts/by-slug/:slug - Gets a post by its slug with Cache-Aside pattern
+// @Summary Get a blog post by slug
+// @Description Retrieves a post by its URL slug with Redis caching (5-minute TTL). Slugs a post had before its title changed answer with 301 and a Location header pointing at the current slug.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param slug path string true "Post slug"
+// @Success 200 {object} models.Post
+// @Success 301 {object} models.SlugRedirectResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /posts/by-slug/{slug} [get]
+func (h *Handler) GetPostBySlug(c *gin.Context) {
+	requested := c.Param("slug")
+
+	id, err := h.resolveSlug(c.Request.Context(), requested)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
 	}
 
-	// Cache miss - get from database
-	var post models.Post
-	if err := h.DB.First(&post, id).Error; err != nil {
+	post, err := h.findCachedPost(c.Request.Context(), id)
+	if err != nil || !canReadPost(c, &post) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
 		return
 	}
 
-	// Cache the result with 5 minutes TTL
-	postJSON, _ := json.Marshal(post)
-	h.Redis.Set(ctx, cacheKey, postJSON, 5*time.Minute)
+	if post.Slug != requested {
+		location := "/api/v1/posts/by-slug/" + url.PathEscape(post.Slug)
+		c.Header("Location", location)
+		c.JSON(http.StatusMovedPermanently, gin.H{
+			"slug":     post.Slug,
+			"location": location,
+		})
+		return
+	}
 
 	c.JSON(http.StatusOK, post)
 }
 
+// findCachedPost loads a post through the cache, filling the cache on a miss.
+// Cached posts carry their rendered HTML so it isn't re-rendered per read.
+func (h *Handler) findCachedPost(ctx context.Context, id uint) (models.Post, error) {
+	cacheKey := cache.PostKey(id)
+
+	// Try the cache first (Cache-Aside pattern)
+	var post models.Post
+	cachedData, err := h.Cache.Get(ctx, cacheKey)
+	if err == nil && json.Unmarshal(cachedData, &post) == nil {
+		metrics.RecordPostCacheLookup(true)
+		return post, nil
+	}
+	metrics.RecordPostCacheLookup(false)
+
+	// Cache miss - get from the repository
+	post, err = h.Posts.Find(ctx, id)
+	if err != nil {
+		return models.Post{}, err
+	}
+	renderContent(&post)
+	decoratePostMedia([]models.Post{post})
+
+	// Cache the result, rendered HTML included, with 5 minutes TTL
+	postJSON, _ := json.Marshal(post)
+	h.Cache.Set(ctx, cacheKey, postJSON, 5*time.Minute)
+
+	return post, nil
+}
+
+// resolveSlug returns the ID of the post a current or former slug belongs to.
+// The mapping is cached; a slug only ever points at one post, so renames don't
+// need to invalidate it.
+func (h *Handler) resolveSlug(ctx context.Context, postSlug string) (uint, error) {
+	cacheKey := cache.PostSlugKey(postSlug)
+
+	if cached, err := h.Cache.Get(ctx, cacheKey); err == nil {
+		if id, err := strconv.ParseUint(string(cached), 10, 32); err == nil {
+			return uint(id), nil
+		}
+	}
+
+	id, err := h.Posts.FindIDBySlug(ctx, postSlug)
+	if err != nil {
+		return 0, err
+	}
+
+	h.Cache.Set(ctx, cacheKey, []byte(strconv.FormatUint(uint64(id), 10)), 5*time.Minute)
+	return id, nil
+}
+
 // GetPostWithRelated handles GET /posts/:id/related - Gets a post with related posts
 // @Summary Get a post with related posts
-// @Description Retrieves a post by ID along with related posts based on tag similarity using Elasticsearch
+// @Description Retrieves a post by ID along with related posts based on tag similarity using the search index
 // @Tags posts
 // @Accept json
 // @Produce json
@@ -142,20 +208,21 @@ func (h *Handler) GetPostWithRelated(c *gin.Context) {
 		return
 	}
 
-	// Get the main post from database
-	var post models.Post
-	if err := h.DB.First(&post, id).Error; err != nil {
+	// Get the main post
+	post, err := h.findCachedPost(c.Request.Context(), uint(id))
+	if err != nil || !canReadPost(c, &post) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
 		return
 	}
 
-	// Find related posts using Elasticsearch
-	relatedPosts, err := h.findRelatedPosts(post)
+	// Find related posts using the search index
+	relatedPosts, err := h.findRelatedPosts(c, post)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find related posts"})
 		return
 	}
 
+	renderContents(relatedPosts)
 	result := models.PostWithRelated{
 		Post:         post,
 		RelatedPosts: relatedPosts,
@@ -166,16 +233,23 @@ func (h *Handler) GetPostWithRelated(c *gin.Context) {
 
 // GetActivityLogs handles GET /activity-logs - Gets all activity logs with pagination
 // @Summary Get activity logs
-// @Description Retrieves all system activity logs with pagination support
+// @Description Retrieves all system activity logs with pagination support. Entries about purged posts keep their post_id but have no post attached. Admin only.
 // @Tags activity-logs
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(20)
+// @Security BearerAuth
 // @Success 200 {object} models.ActivityLogsResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /activity-logs [get]
 func (h *Handler) GetActivityLogs(c *gin.Context) {
+	if !authorize(c, policy.ReadActivityLogs, nil) {
+		return
+	}
+
 	// Parse pagination parameters
 	pageStr := c.DefaultQuery("page", "1")
 	limitStr := c.DefaultQuery("limit", "20")
@@ -192,17 +266,18 @@ func (h *Handler) GetActivityLogs(c *gin.Context) {
 
 	offset := (page - 1) * limit
 
-	var logs []models.ActivityLog
-	var total int64
-	
+	ctx := c.Request.Context()
+
 	// Get total count
-	if err := h.DB.Model(&models.ActivityLog{}).Count(&total).Error; err != nil {
+	total, err := h.ActivityLogs.Count(ctx)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count activity logs"})
 		return
 	}
 
-	// Get logs with pagination, ordered by logged_at descending
-	if err := h.DB.Preload("Post").Order("logged_at DESC").Offset(offset).Limit(limit).Find(&logs).Error; err != nil {
+	// Get logs with pagination, newest first
+	logs, err := h.ActivityLogs.List(ctx, offset, limit)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch activity logs"})
 		return
 	}
@@ -225,48 +300,21 @@ func (h *Handler) GetActivityLogs(c *gin.Context) {
 		})
 }
 
-// findRelatedPosts finds posts related to the given post based on tags using Elasticsearch
-func (h *Handler) findRelatedPosts(post models.Post) ([]models.Post, error) {
-	ctx := context.Background()
+// findRelatedPosts finds posts related to the given post based on tags using the
+// search index, limited to posts the caller may read
+func (h *Handler) findRelatedPosts(c *gin.Context, post models.Post) ([]models.Post, error) {
+	ctx := c.Request.Context()
 
 	// If the post has no tags, return empty slice
 	if len(post.Tags) == 0 {
 		return []models.Post{}, nil
 	}
 
-	// Create a bool query with should clauses for each tag
-	boolQuery := elastic.NewBoolQuery()
-	
-	// Add should clauses for each tag (OR logic)
-	for _, tag := range post.Tags {
-		termQuery := elastic.NewTermQuery("tags", tag)
-		boolQuery = boolQuery.Should(termQuery)
-	}
-	
-	// Exclude the current post from results
-	boolQuery = boolQuery.MustNot(elastic.NewTermQuery("id", post.ID))
-	
-	// Set minimum should match to ensure at least one tag matches
-	boolQuery = boolQuery.MinimumShouldMatch("1")
-
-	// Execute the search
-	searchResult, err := h.ES.Search().
-		Index("posts").
-		Query(boolQuery).
-		Size(5). // Limit to 5 related posts
-		Do(ctx)
-
+	// Limit to 5 related posts, hiding posts the caller isn't allowed to read
+	visibility := postVisibility(c)
+	postIDs, err := h.Search.Related(ctx, post, visibility, 5)
 	if err != nil {
-		return nil, fmt.Errorf("elasticsearch search failed: %v", err)
-	}
-
-	// Extract post IDs from search results
-	var postIDs []uint
-	for _, hit := range searchResult.Hits.Hits {
-		var searchPost models.PostSearchResult
-		if err := json.Unmarshal(hit.Source, &searchPost); err == nil {
-			postIDs = append(postIDs, searchPost.ID)
-		}
+		return nil, fmt.Errorf("search failed: %v", err)
 	}
 
 	// If no related posts found, return empty slice
@@ -274,10 +322,10 @@ func (h *Handler) findRelatedPosts(post models.Post) ([]models.Post, error) {
 		return []models.Post{}, nil
 	}
 
-	// Fetch full post data from database
-	var relatedPosts []models.Post
-	if err := h.DB.Where("id IN ?", postIDs).Find(&relatedPosts).Error; err != nil {
-		return nil, fmt.Errorf("database query failed: %v", err)
+	// Fetch full post data from the repository
+	relatedPosts, err := h.Posts.List(ctx, repository.PostFilter{Visibility: visibility, IDs: postIDs}, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("post lookup failed: %v", err)
 	}
 
 	return relatedPosts, nil
@@ -285,14 +333,17 @@ func (h *Handler) findRelatedPosts(post models.Post) ([]models.Post, error) {
 
 // UpdatePost handles PUT /posts/:id - Updates a post with cache invalidation
 // @Summary Update a blog post
-// @Description Updates a post and invalidates the cache
+// @Description Updates a post, records the result as a new revision and invalidates the cache. Authors may edit their own posts, editors and admins any post.
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Param id path int true "Post ID"
 // @Param post body models.UpdatePostRequest true "Post update request"
+// @Security BearerAuth
 // @Success 200 {object} models.Post
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /posts/{id} [put]
@@ -310,44 +361,50 @@ func (h *Handler) UpdatePost(c *gin.Context) {
 		return
 	}
 
-	// Find existing post
-	var post models.Post
-	if err := h.DB.First(&post, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
-		return
-	}
-
-	// Update fields if provided
-	if req.Title != "" {
-		post.Title = req.Title
-	}
-	if req.Content != "" {
-		post.Content = req.Content
-	}
-	if req.Tags != nil {
-		post.Tags = models.StringArray(req.Tags)
-	}
-
-	// Save to database
-	if err := h.DB.Save(&post).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update post"})
+	var oldTags models.StringArray
+	post, err := h.Posts.Update(c.Request.Context(), uint(id), repository.PostEdit{
+		Check: func(post *models.Post) error {
+			if !authorize(c, policy.EditPost, post) {
+				return errResponded
+			}
+			oldTags = post.Tags
+			return nil
+		},
+		Apply: func(post *models.Post) {
+			if req.Title != "" {
+				post.Title = req.Title
+			}
+			if req.Content != "" {
+				post.Content = req.Content
+			}
+			if req.ContentFormat != "" {
+				post.ContentFormat = req.ContentFormat
+			}
+			if req.Tags != nil {
+				post.Tags = models.StringArray(req.Tags)
+			}
+		},
+		Revise:   true,
+		EditorID: editorID(c),
+	})
+	if err != nil {
+		respondPostError(c, err, "Failed to update post")
 		return
 	}
 
 	// Invalidate cache
-	ctx := context.Background()
-	cacheKey := fmt.Sprintf("post:%d", id)
-	h.Redis.Del(ctx, cacheKey)
-
-	// Update in Elasticsearch
-	go h.indexPostInES(post)
+	ctx := detached(c)
+	cacheKey := cache.PostKey(uint(id))
+	h.Cache.Delete(ctx, cacheKey)
+	h.invalidateFeeds(ctx, append(oldTags, post.Tags...)...)
 
+	renderContent(&post)
 	c.JSON(http.StatusOK, post)
 }
 
 // SearchPostsByTag handles GET /posts/search-by-tag?tag=<tag_name>
 // @Summary Search posts by tag
-// @Description Searches posts containing a specific tag using optimized GIN indexing
+// @Description Searches posts containing a specific tag using optimized GIN indexing. Anonymous readers only see published posts.
 // @Tags posts
 // @Accept json
 // @Produce json
@@ -363,13 +420,12 @@ func (h *Handler) SearchPostsByTag(c *gin.Context) {
 		return
 	}
 
-	var posts []models.Post
-	// Use GIN index for efficient tag searching
-	err := h.DB.Where("tags @> ARRAY[?]", tag).Find(&posts).Error
+	posts, err := h.Posts.List(c.Request.Context(), repository.PostFilter{Visibility: postVisibility(c), Tag: tag}, 0, 0)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search posts"})
 		return
 	}
+	renderContents(posts)
 
 	c.JSON(http.StatusOK, gin.H{
 		"posts": posts,
@@ -379,7 +435,7 @@ func (h *Handler) SearchPostsByTag(c *gin.Context) {
 
 // SearchPosts handles GET /posts/search?q=<query_string>
 // @Summary Full-text search posts
-// @Description Performs full-text search across post titles and content using Elasticsearch
+// @Description Performs full-text search across post titles and content using the search index. Hits carry content_html like other post responses. Anonymous readers only see published posts.
 // @Tags posts
 // @Accept json
 // @Produce json
@@ -395,42 +451,29 @@ func (h *Handler) SearchPosts(c *gin.Context) {
 		return
 	}
 
-	ctx := context.Background()
-
-	// Create multi-match query for title and content
-	searchQuery := elastic.NewMultiMatchQuery(query, "title", "content").
-		Type("best_fields").
-		Fuzziness("AUTO")
-
-	searchResult, err := h.ES.Search().
-		Index("posts").
-		Query(searchQuery).
-		Size(50).
-		Do(ctx)
+	ctx := c.Request.Context()
 
+	// Hide posts the caller isn't allowed to read
+	started := time.Now()
+	results, err := h.Search.Search(ctx, query, postVisibility(c), 50)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
 		return
 	}
+	metrics.RecordSearch(results.Backend, time.Since(started), results.TookInMillis)
 
-	var posts []models.PostSearchResult
-	for _, hit := range searchResult.Hits.Hits {
-		var post models.PostSearchResult
-		if err := json.Unmarshal(hit.Source, &post); err == nil {
-			posts = append(posts, post)
-		}
-	}
+	renderSearchResults(results.Posts)
 
 	c.JSON(http.StatusOK, gin.H{
-		"posts": posts,
-		"total": searchResult.Hits.TotalHits.Value,
-		"took":  searchResult.TookInMillis,
+		"posts": results.Posts,
+		"total": results.Total,
+		"took":  results.TookInMillis,
 	})
 }
 
 // GetAllPosts handles GET /posts - Gets all posts with pagination
 // @Summary Get all blog posts
-// @Description Retrieves all posts with pagination support
+// @Description Retrieves all posts with pagination support. Anonymous readers only see published posts.
 // @Tags posts
 // @Accept json
 // @Produce json
@@ -456,20 +499,24 @@ func (h *Handler) GetAllPosts(c *gin.Context) {
 
 	offset := (page - 1) * limit
 
-	var posts []models.Post
-	var total int64
-	
+	ctx := c.Request.Context()
+	filter := repository.PostFilter{Visibility: postVisibility(c)}
+
 	// Get total count
-	if err := h.DB.Model(&models.Post{}).Count(&total).Error; err != nil {
+	total, err := h.Posts.Count(ctx, filter)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count posts"})
 		return
 	}
 
 	// Get posts with pagination, ordered by created_at descending
-	if err := h.DB.Order("created_at DESC").Offset(offset).Limit(limit).Find(&posts).Error; err != nil {
+	posts, err := h.Posts.List(ctx, filter, offset, limit)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
 		return
 	}
+	renderContents(posts)
+	decoratePostMedia(posts)
 
 	// Calculate pagination info
 	totalPages := int((total + int64(limit) - 1) / int64(limit))
@@ -489,15 +536,18 @@ func (h *Handler) GetAllPosts(c *gin.Context) {
 	})
 }
 
-// DeletePost handles DELETE /posts/:id - Deletes a post with cache invalidation
+// DeletePost handles DELETE /posts/:id - Moves a post to the trash with cache invalidation
 // @Summary Delete a blog post
-// @Description Deletes a post and cleans up related data with cache invalidation
+// @Description Soft-deletes a post, removing it from the cache and search index. Trashed posts keep their comments, revisions and activity logs and can be restored. Authors and editors may delete their own posts, admins any post.
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Param id path int true "Post ID"
+// @Security BearerAuth
 // @Success 200 {object} models.SuccessResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /posts/{id} [delete]
@@ -509,94 +559,63 @@ func (h *Handler) DeletePost(c *gin.Context) {
 		return
 	}
 
-	// Start transaction
-	tx := h.DB.Begin()
-	if tx.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
-		return
-	}
-
-	// Check if post exists
-	var post models.Post
-	if err := tx.First(&post, id).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
-		return
-	}
-
-	// Delete related activity logs first
-	if err := tx.Where("post_id = ?", id).Delete(&models.ActivityLog{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete activity logs"})
-		return
-	}
-
-	// Delete the post
-	if err := tx.Delete(&post).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete post"})
-		return
-	}
-
-	// Create deletion activity log AFTER deleting the post (with null PostID since post is gone)
-	if err := tx.Exec("INSERT INTO activity_logs (action, post_id) VALUES ($1, NULL)", "delete_post").Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create activity log"})
-		return
-	}
-
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+	// Move the post to the trash. Comments, revisions and activity logs are kept
+	// so a restore brings the post back intact.
+	post, err := h.Posts.Delete(c.Request.Context(), uint(id), func(post *models.Post) error {
+		if !authorize(c, policy.DeletePost, post) {
+			return errResponded
+		}
+		return nil
+	})
+	if err != nil {
+		respondPostError(c, err, "Failed to delete post")
 		return
 	}
 
 	// Invalidate cache
-	ctx := context.Background()
-	cacheKey := fmt.Sprintf("post:%d", id)
-	h.Redis.Del(ctx, cacheKey)
-
-	// Delete from Elasticsearch
-	go h.deletePostFromES(uint(id))
+	ctx := detached(c)
+	cacheKey := cache.PostKey(uint(id))
+	h.Cache.Delete(ctx, cacheKey)
+	h.invalidateFeeds(ctx, post.Tags...)
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Post deleted successfully",
+		"message": "Post moved to trash",
 		"id":      id,
 	})
 }
 
-// indexPostInES indexes a post in Elasticsearch
-func (h *Handler) indexPostInES(post models.Post) {
-	ctx := context.Background()
+// renderContent fills in the post's sanitized HTML from its content and format
+func renderContent(post *models.Post) {
+	post.ContentHTML = render.HTML(post.Content, post.ContentFormat)
+}
 
-	doc := models.PostSearchResult{
-		ID:      post.ID,
-		Title:   post.Title,
-		Content: post.Content,
-		Tags:    []string(post.Tags),
+// renderContents renders a list of posts in place
+func renderContents(posts []models.Post) {
+	for i := range posts {
+		renderContent(&posts[i])
 	}
+}
 
-	_, err := h.ES.Index().
-		Index("posts").
-		Id(fmt.Sprintf("%d", post.ID)).
-		BodyJson(doc).
-		Do(ctx)
-
-	if err != nil {
-		fmt.Printf("Failed to index post in Elasticsearch: %v\n", err)
+// renderSearchResults renders search hits in place, like renderContents
+func renderSearchResults(hits []models.PostSearchResult) {
+	for i := range hits {
+		hits[i].ContentHTML = render.HTML(hits[i].Content, hits[i].ContentFormat)
 	}
 }
 
-// deletePostFromES deletes a post from Elasticsearch
-func (h *Handler) deletePostFromES(postID uint) {
-	ctx := context.Background()
-
-	_, err := h.ES.Delete().
-		Index("posts").
-		Id(fmt.Sprintf("%d", postID)).
-		Do(ctx)
-
-	if err != nil {
-		fmt.Printf("Failed to delete post from Elasticsearch: %v\n", err)
+// errResponded is returned from repository callbacks that have already
+// written the response, such as a failed authorization check
+var errResponded = errors.New("response already written")
+
+// respondPostError writes the response for an error from a post repository
+// write: 404 for a missing post, nothing if a callback already responded, and
+// a 500 with message otherwise
+func respondPostError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, errResponded):
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
 	}
 }
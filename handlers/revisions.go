@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/susbuntu/blog-api/auth"
+	"github.com/susbuntu/blog-api/cache"
+	"github.com/susbuntu/blog-api/diff"
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/policy"
+	"github.com/susbuntu/blog-api/repository"
+)
+
+// diffContextLines is how many unchanged lines surround each hunk in revision diffs
+const diffContextLines = 3
+
+// GetRevisions handles GET /posts/:id/revisions - Lists a post's revisions, newest first
+// @Summary List post revisions
+// @Description Lists the revision history of a post with pagination, newest first. Available to whoever can edit the post.
+// @Tags revisions
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Security BearerAuth
+// @Success 200 {object} models.RevisionsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /posts/{id}/revisions [get]
+func (h *Handler) GetRevisions(c *gin.Context) {
+	post, ok := h.findPostForRevisions(c)
+	if !ok {
+		return
+	}
+
+	// Parse pagination parameters
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	offset := (page - 1) * limit
+
+	var revisions []models.PostRevision
+	var total int64
+
+	if err := h.db(c).Model(&models.PostRevision{}).Where("post_id = ?", post.ID).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count revisions"})
+		return
+	}
+
+	if err := h.db(c).Where("post_id = ?", post.ID).Order("revision DESC").Offset(offset).Limit(limit).Find(&revisions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch revisions"})
+		return
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	c.JSON(http.StatusOK, gin.H{
+		"revisions": revisions,
+		"pagination": gin.H{
+			"current_page": page,
+			"total_pages":  totalPages,
+			"total_count":  total,
+			"limit":        limit,
+			"has_next":     page < totalPages,
+			"has_prev":     page > 1,
+		},
+	})
+}
+
+// GetRevision handles GET /posts/:id/revisions/:revision - Gets a single revision
+// @Summary Get a post revision
+// @Description Retrieves one revision of a post by its revision number
+// @Tags revisions
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param revision path int true "Revision number"
+// @Security BearerAuth
+// @Success 200 {object} models.PostRevision
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /posts/{id}/revisions/{revision} [get]
+func (h *Handler) GetRevision(c *gin.Context) {
+	post, ok := h.findPostForRevisions(c)
+	if !ok {
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision number"})
+		return
+	}
+
+	var revision models.PostRevision
+	if err := h.db(c).Where("post_id = ? AND revision = ?", post.ID, number).First(&revision).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
+
+// DiffRevisions handles GET /posts/:id/revisions/diff?from=<n>&to=<m> - Diffs two revisions
+// @Summary Diff two post revisions
+// @Description Returns a unified diff of the title, content format, tags and content between two revisions of a post. Use format=text to get a plain text/x-diff body. Revisions too large or too different to compare line by line are reported as differing without a line diff.
+// @Tags revisions
+// @Accept json
+// @Produce json,plain
+// @Param id path int true "Post ID"
+// @Param from query int true "Older revision number"
+// @Param to query int true "Newer revision number"
+// @Param format query string false "Response format" Enums(json, text) default(json)
+// @Security BearerAuth
+// @Success 200 {object} models.RevisionDiffResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /posts/{id}/revisions/diff [get]
+func (h *Handler) DiffRevisions(c *gin.Context) {
+	post, ok := h.findPostForRevisions(c)
+	if !ok {
+		return
+	}
+
+	from, errFrom := strconv.Atoi(c.Query("from"))
+	to, errTo := strconv.Atoi(c.Query("to"))
+	if errFrom != nil || errTo != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to must be revision numbers"})
+		return
+	}
+
+	var revisions []models.PostRevision
+	if err := h.db(c).Where("post_id = ? AND revision IN ?", post.ID, []int{from, to}).Find(&revisions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch revisions"})
+		return
+	}
+
+	byNumber := make(map[int]models.PostRevision, len(revisions))
+	for _, revision := range revisions {
+		byNumber[revision.Revision] = revision
+	}
+	fromRevision, okFrom := byNumber[from]
+	toRevision, okTo := byNumber[to]
+	if !okFrom || !okTo {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		return
+	}
+
+	unified := diff.Unified(
+		fmt.Sprintf("revision %d", from),
+		fmt.Sprintf("revision %d", to),
+		revisionText(fromRevision),
+		revisionText(toRevision),
+		diffContextLines,
+	)
+
+	if c.Query("format") == "text" {
+		c.Data(http.StatusOK, "text/x-diff; charset=utf-8", []byte(unified))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.RevisionDiffResponse{
+		PostID: post.ID,
+		From:   from,
+		To:     to,
+		Diff:   unified,
+	})
+}
+
+// RestoreRevision handles POST /posts/:id/revisions/:revision/restore - Restores an old revision
+// @Summary Restore a post revision
+// @Description Copies the title, content, content format and tags of an old revision back onto the post, recording the result as a new revision
+// @Tags revisions
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param revision path int true "Revision number to restore"
+// @Security BearerAuth
+// @Success 200 {object} models.Post
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /posts/{id}/revisions/{revision}/restore [post]
+func (h *Handler) RestoreRevision(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision number"})
+		return
+	}
+
+	var restored models.PostRevision
+	var oldTags models.StringArray
+	post, err := h.Posts.Update(c.Request.Context(), uint(id), repository.PostEdit{
+		Check: func(post *models.Post) error {
+			if !authorize(c, policy.EditPost, post) {
+				return errResponded
+			}
+
+			var revision models.PostRevision
+			if err := h.db(c).Where("post_id = ? AND revision = ?", post.ID, number).First(&revision).Error; err != nil {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+				return errResponded
+			}
+			restored = revision
+			return nil
+		},
+		Apply: func(post *models.Post) {
+			oldTags = post.Tags
+			post.Title = restored.Title
+			post.Content = restored.Content
+			post.ContentFormat = restored.ContentFormat
+			post.Tags = restored.Tags
+		},
+		Revise:       true,
+		EditorID:     editorID(c),
+		RestoredFrom: &number,
+		LogAction:    "restore_revision",
+	})
+	if err != nil {
+		respondPostError(c, err, "Failed to restore post")
+		return
+	}
+
+	// Invalidate cache
+	ctx := detached(c)
+	h.Cache.Delete(ctx, cache.PostKey(post.ID))
+	h.invalidateFeeds(ctx, append(oldTags, post.Tags...)...)
+
+	renderContent(&post)
+	c.JSON(http.StatusOK, post)
+}
+
+// findPostForRevisions loads the post addressed by :id and checks the caller may
+// read its history, writing an error response and returning false otherwise
+func (h *Handler) findPostForRevisions(c *gin.Context) (models.Post, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return models.Post{}, false
+	}
+
+	var post models.Post
+	if err := h.db(c).First(&post, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return models.Post{}, false
+	}
+
+	if !authorize(c, policy.ReadRevisions, &post) {
+		return models.Post{}, false
+	}
+
+	return post, true
+}
+
+// editorID is the user to credit with a revision, if the caller is signed in
+func editorID(c *gin.Context) *uint {
+	if userID, ok := auth.UserID(c); ok {
+		return &userID
+	}
+	return nil
+}
+
+// revisionText renders a revision as plain text for diffing
+func revisionText(revision models.PostRevision) string {
+	return fmt.Sprintf("title: %s\nformat: %s\ntags: %s\n\n%s", revision.Title, revision.ContentFormat, strings.Join(revision.Tags, ", "), revision.Content)
+}
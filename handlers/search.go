@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/policy"
+	"github.com/susbuntu/blog-api/search"
+	"gorm.io/gorm"
+)
+
+// StartReindex handles POST /admin/search/reindex - Rebuilds the search index
+// @Summary Rebuild the search index
+// @Description Starts a zero-downtime reindex in the background: a new versioned index is loaded from Postgres, caught up with writes made during the copy, and swapped in behind the posts alias. Search keeps working throughout. Admin only.
+// @Tags search
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} models.SuccessResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/search/reindex [post]
+func (h *Handler) StartReindex(c *gin.Context) {
+	if !authorize(c, policy.ManageSearch, nil) {
+		return
+	}
+
+	// The reindex outlives the request, so it keeps the request's trace but not its cancellation
+	ctx := detached(c)
+	err := search.StartReindex(ctx, h.ES, h.DB.WithContext(ctx), func(result *search.ReindexResult, err error) {
+		if err == nil {
+			log.Printf("Admin reindex finished: %s replaces %v", result.Index, result.Previous)
+		}
+	})
+	if errors.Is(err, search.ErrReindexRunning) {
+		c.JSON(http.StatusConflict, gin.H{"error": "A reindex is already running"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start reindex"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Reindex started"})
+}
+
+// GetSearchIndices handles GET /admin/search/indices - Lists the posts indices
+// @Summary List search indices
+// @Description Lists the versioned posts indices with their document counts and which one the posts alias points at. Admin only.
+// @Tags search
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} object{indices=[]search.IndexInfo}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/search/indices [get]
+func (h *Handler) GetSearchIndices(c *gin.Context) {
+	if !authorize(c, policy.ManageSearch, nil) {
+		return
+	}
+
+	indices, err := search.Indices(c.Request.Context(), h.ES)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list search indices"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"indices": indices})
+}
+
+// StartConsistencyCheck handles POST /admin/search/consistency - Checks the search index against Postgres
+// @Summary Check search index consistency
+// @Description Starts a consistency check in the background that compares every live post with its search document by ID and content hash, and scrolls the index for orphaned documents. With repair=true, index and delete events for the drift are queued on the outbox. Fetch the result with GET /admin/search/consistency. Admin only.
+// @Tags search
+// @Produce json
+// @Param repair query bool false "Queue fixes for the drift found" default(false)
+// @Security BearerAuth
+// @Success 202 {object} models.SuccessResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/search/consistency [post]
+func (h *Handler) StartConsistencyCheck(c *gin.Context) {
+	if !authorize(c, policy.ManageSearch, nil) {
+		return
+	}
+
+	repair := c.Query("repair") == "true"
+
+	// The check outlives the request, so it keeps the request's trace but not its cancellation
+	ctx := detached(c)
+	err := search.StartConsistencyCheck(ctx, h.ES, h.DB.WithContext(ctx), repair, func(*models.ConsistencyCheck, error) {})
+	if errors.Is(err, search.ErrConsistencyCheckRunning) {
+		c.JSON(http.StatusConflict, gin.H{"error": "A consistency check is already running"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start consistency check"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Consistency check started"})
+}
+
+// GetConsistencyCheck handles GET /admin/search/consistency - Shows the latest consistency check
+// @Summary Get the latest consistency check
+// @Description Returns the result of the most recent search index consistency check: how many posts and documents were compared, and the count and first IDs of missing, stale and orphaned documents. Admin only.
+// @Tags search
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.ConsistencyCheck
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/search/consistency [get]
+func (h *Handler) GetConsistencyCheck(c *gin.Context) {
+	if !authorize(c, policy.ManageSearch, nil) {
+		return
+	}
+
+	var check models.ConsistencyCheck
+	err := h.db(c).Order("id DESC").First(&check).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No consistency check has run yet"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch consistency check"})
+		return
+	}
+
+	c.JSON(http.StatusOK, check)
+}
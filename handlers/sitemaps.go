@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/sitemap"
+	"gorm.io/gorm"
+)
+
+// sitemapBatchSize is how many rows are read from Postgres at a time while a
+// sitemap is streamed
+const sitemapBatchSize = 1000
+
+const sitemapContentType = "application/xml; charset=utf-8"
+
+// Child sitemap kinds, served as /sitemaps/<kind>-<page>.xml
+const (
+	sitemapPosts = "posts"
+	sitemapTags  = "tags"
+)
+
+// publishedTagsFrom selects every tag of every published post, one row per tag use
+const publishedTagsFrom = `FROM posts, unnest(posts.tags) AS tag
+	WHERE posts.status = ? AND posts.deleted_at IS NULL AND tag <> ''`
+
+// GetSitemap handles GET /sitemap.xml - Sitemap of published posts and tags
+// @Summary Sitemap
+// @Description Lists every published post and tag landing page. Once there are more than 50,000 URLs this becomes a sitemap index pointing at paged child sitemaps. Also served at /sitemap.xml on the site root.
+// @Tags sitemap
+// @Produce xml
+// @Success 200 {string} string "Sitemap or sitemap index"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /sitemap.xml [get]
+func (h *Handler) GetSitemap(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var postCount, tagCount int64
+	if err := h.publishedPosts(ctx).Count(&postCount).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count posts"})
+		return
+	}
+	if err := h.countPublishedTags(ctx, &tagCount); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count tags"})
+		return
+	}
+
+	if postCount+tagCount <= sitemap.MaxURLs {
+		h.streamSitemap(c, func(w *sitemap.Writer) error {
+			if err := h.writePostURLs(ctx, w, 0, int(postCount)); err != nil {
+				return err
+			}
+			return h.writeTagURLs(ctx, w, 0, int(tagCount))
+		})
+		return
+	}
+
+	c.Header("Content-Type", sitemapContentType)
+	w, err := sitemap.NewIndex(c.Writer)
+	if err == nil {
+		err = h.writeSitemapPages(w, sitemapPosts, postCount)
+	}
+	if err == nil {
+		err = h.writeSitemapPages(w, sitemapTags, tagCount)
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		log.Printf("Failed to write sitemap index: %v", err)
+	}
+}
+
+// GetSitemapPage handles GET /sitemaps/:name - One page of a sitemap index
+// @Summary Child sitemap
+// @Description Serves one page of the sitemap index, such as posts-2.xml or tags-1.xml, with up to 50,000 URLs. Pages past the end are 404. Also served at /sitemaps/{name} on the site root.
+// @Tags sitemap
+// @Produce xml
+// @Param name path string true "Child sitemap name, e.g. posts-1.xml"
+// @Success 200 {string} string "Sitemap"
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /sitemaps/{name} [get]
+func (h *Handler) GetSitemapPage(c *gin.Context) {
+	kind, page, ok := parseSitemapName(c.Param("name"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sitemap not found"})
+		return
+	}
+	offset := (page - 1) * sitemap.MaxURLs
+	ctx := c.Request.Context()
+
+	// Pages past the end are missing rather than empty, so crawlers drop stale ones
+	var count int64
+	var err error
+	if kind == sitemapPosts {
+		err = h.publishedPosts(ctx).Count(&count).Error
+	} else {
+		err = h.countPublishedTags(ctx, &count)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count sitemap entries"})
+		return
+	}
+	if int64(offset) >= count {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sitemap not found"})
+		return
+	}
+
+	h.streamSitemap(c, func(w *sitemap.Writer) error {
+		if kind == sitemapPosts {
+			return h.writePostURLs(ctx, w, offset, sitemap.MaxURLs)
+		}
+		return h.writeTagURLs(ctx, w, offset, sitemap.MaxURLs)
+	})
+}
+
+// streamSitemap writes a <urlset> straight to the response. Once streaming has
+// started the status can't change, so failures are logged and the document is
+// left truncated.
+func (h *Handler) streamSitemap(c *gin.Context, write func(*sitemap.Writer) error) {
+	c.Header("Content-Type", sitemapContentType)
+
+	w, err := sitemap.NewURLSet(c.Writer)
+	if err == nil {
+		err = write(w)
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		log.Printf("Failed to write sitemap: %v", err)
+	}
+}
+
+// writeSitemapPages lists the child sitemaps needed for count URLs of a kind
+func (h *Handler) writeSitemapPages(w *sitemap.Writer, kind string, count int64) error {
+	pages := int((count + sitemap.MaxURLs - 1) / sitemap.MaxURLs)
+	for page := 1; page <= pages; page++ {
+		loc := fmt.Sprintf("%s/sitemaps/%s-%d.xml", h.Config.SiteURL, kind, page)
+		if err := w.Add(loc, time.Time{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writePostURLs streams up to limit published posts, skipping the first offset.
+// Posts are read in ID order a batch at a time, seeking past the last ID seen
+// rather than using ever larger offsets.
+func (h *Handler) writePostURLs(ctx context.Context, w *sitemap.Writer, offset, limit int) error {
+	var lastID uint
+	if offset > 0 {
+		var ids []uint
+		if err := h.publishedPosts(ctx).Order("id").Offset(offset-1).Limit(1).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		lastID = ids[0]
+	}
+
+	for written := 0; written < limit; {
+		var posts []models.Post
+		err := h.publishedPosts(ctx).Select("id", "slug", "updated_at").
+			Where("id > ?", lastID).
+			Order("id").
+			Limit(min(sitemapBatchSize, limit-written)).
+			Find(&posts).Error
+		if err != nil {
+			return err
+		}
+
+		for _, post := range posts {
+			if err := w.Add(h.postLink(post.Slug), post.UpdatedAt); err != nil {
+				return err
+			}
+		}
+		if len(posts) < sitemapBatchSize {
+			return nil
+		}
+		written += len(posts)
+		lastID = posts[len(posts)-1].ID
+	}
+	return nil
+}
+
+// writeTagURLs streams up to limit tag landing pages in name order, skipping
+// the first offset. Each tag's lastmod is the newest update among its posts.
+func (h *Handler) writeTagURLs(ctx context.Context, w *sitemap.Writer, offset, limit int) error {
+	type tagRow struct {
+		Tag       string
+		UpdatedAt time.Time
+	}
+
+	lastTag := ""
+	if offset > 0 {
+		var tags []string
+		err := h.DB.WithContext(ctx).Raw("SELECT DISTINCT tag "+publishedTagsFrom+" ORDER BY tag OFFSET ? LIMIT 1", models.PostPublished, offset-1).
+			Scan(&tags).Error
+		if err != nil {
+			return err
+		}
+		if len(tags) == 0 {
+			return nil
+		}
+		lastTag = tags[0]
+	}
+
+	for written := 0; written < limit; {
+		var rows []tagRow
+		err := h.DB.WithContext(ctx).Raw("SELECT tag, MAX(posts.updated_at) AS updated_at "+publishedTagsFrom+" AND tag > ? GROUP BY tag ORDER BY tag LIMIT ?",
+			models.PostPublished, lastTag, min(sitemapBatchSize, limit-written)).
+			Scan(&rows).Error
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			if err := w.Add(h.tagLink(row.Tag), row.UpdatedAt); err != nil {
+				return err
+			}
+		}
+		if len(rows) < sitemapBatchSize {
+			return nil
+		}
+		written += len(rows)
+		lastTag = rows[len(rows)-1].Tag
+	}
+	return nil
+}
+
+// countPublishedTags counts the distinct tags of published posts
+func (h *Handler) countPublishedTags(ctx context.Context, count *int64) error {
+	return h.DB.WithContext(ctx).Raw("SELECT COUNT(DISTINCT tag) "+publishedTagsFrom, models.PostPublished).Scan(count).Error
+}
+
+// publishedPosts starts a query over the posts listed publicly
+func (h *Handler) publishedPosts(ctx context.Context) *gorm.DB {
+	return h.DB.WithContext(ctx).Model(&models.Post{}).Where("status = ?", models.PostPublished)
+}
+
+// parseSitemapName splits a child sitemap name like "posts-2.xml" into its kind and page
+func parseSitemapName(name string) (string, int, bool) {
+	base, ok := strings.CutSuffix(name, ".xml")
+	if !ok {
+		return "", 0, false
+	}
+	i := strings.LastIndex(base, "-")
+	if i < 0 {
+		return "", 0, false
+	}
+
+	kind := base[:i]
+	page, err := strconv.Atoi(base[i+1:])
+	if err != nil || page < 1 || (kind != sitemapPosts && kind != sitemapTags) {
+		return "", 0, false
+	}
+	return kind, page, true
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/susbuntu/blog-api/cache"
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/outbox"
+	"github.com/susbuntu/blog-api/policy"
+	"gorm.io/gorm"
+)
+
+// GetTrash handles GET /posts/trash - Lists soft-deleted posts
+// @Summary List trashed posts
+// @Description Lists soft-deleted posts with pagination, most recently deleted first. Admins see every trashed post; everyone else sees only their own.
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Security BearerAuth
+// @Success 200 {object} models.PostsResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /posts/trash [get]
+func (h *Handler) GetTrash(c *gin.Context) {
+	// Parse pagination parameters
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+
+	trashed := func() *gorm.DB {
+		query := h.db(c).Unscoped().Model(&models.Post{}).Where("deleted_at IS NOT NULL")
+		if subject, _ := currentSubject(c); !policy.Can(subject, policy.ListAllTrash, nil) {
+			query = query.Where("author_id = ?", subject.UserID)
+		}
+		return query
+	}
+
+	var posts []models.Post
+	var total int64
+
+	if err := trashed().Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count posts"})
+		return
+	}
+
+	if err := trashed().Order("deleted_at DESC").Offset(offset).Limit(limit).Find(&posts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
+		return
+	}
+	renderContents(posts)
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	c.JSON(http.StatusOK, gin.H{
+		"posts": posts,
+		"pagination": gin.H{
+			"current_page": page,
+			"total_pages":  totalPages,
+			"total_count":  total,
+			"limit":        limit,
+			"has_next":     page < totalPages,
+			"has_prev":     page > 1,
+		},
+	})
+}
+
+// RestorePost handles POST /posts/:id/restore - Takes a post out of the trash
+// @Summary Restore a trashed post
+// @Description Restores a soft-deleted post with its previous status and puts it back in the search index
+// @Tags trash
+// @Produce json
+// @Param id path int true "Post ID"
+// @Security BearerAuth
+// @Success 200 {object} models.Post
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /posts/{id}/restore [post]
+func (h *Handler) RestorePost(c *gin.Context) {
+	post, ok := h.findTrashedPost(c, policy.RestorePost)
+	if !ok {
+		return
+	}
+
+	err := h.db(c).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&post).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.ActivityLog{Action: "restore_post", PostID: &post.ID}).Error; err != nil {
+			return err
+		}
+		// Put back in Elasticsearch
+		return outbox.IndexPost(tx, post.ID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore post"})
+		return
+	}
+	post.DeletedAt = gorm.DeletedAt{}
+
+	// Drop any stale cache entry so the next read repopulates it
+	ctx := detached(c)
+	h.Cache.Delete(ctx, cache.PostKey(post.ID))
+	h.invalidateFeeds(ctx, post.Tags...)
+
+	renderContent(&post)
+	c.JSON(http.StatusOK, post)
+}
+
+// PurgePost handles DELETE /posts/:id/purge - Permanently deletes a trashed post
+// @Summary Permanently delete a trashed post
+// @Description Hard-deletes a post that is already in the trash, along with its comments, revisions, media attachments and old slugs. Uploaded files are kept. Activity log entries are kept and still carry the post ID, with no post attached. Admins only.
+// @Tags trash
+// @Produce json
+// @Param id path int true "Post ID"
+// @Security BearerAuth
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /posts/{id}/purge [delete]
+func (h *Handler) PurgePost(c *gin.Context) {
+	post, ok := h.findTrashedPost(c, policy.PurgePost)
+	if !ok {
+		return
+	}
+
+	var oldSlugs []string
+	err := h.db(c).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("post_id = ?", post.ID).Delete(&models.Comment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("post_id = ?", post.ID).Delete(&models.PostRevision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("post_id = ?", post.ID).Delete(&models.PostMedia{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.PostSlug{}).Where("post_id = ?", post.ID).Pluck("slug", &oldSlugs).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("post_id = ?", post.ID).Delete(&models.PostSlug{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Delete(&post).Error; err != nil {
+			return err
+		}
+		// The document went when the post was trashed, unless that delivery is still failing
+		if err := outbox.DeletePost(tx, post.ID); err != nil {
+			return err
+		}
+		// The post's activity log entries, this one included, keep its ID as the audit trail
+		return tx.Create(&models.ActivityLog{Action: "purge_post", PostID: &post.ID}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to purge post"})
+		return
+	}
+
+	// The slugs are free for other posts now, so forget where they used to point
+	slugKeys := []string{cache.PostSlugKey(post.Slug)}
+	for _, oldSlug := range oldSlugs {
+		slugKeys = append(slugKeys, cache.PostSlugKey(oldSlug))
+	}
+	h.Cache.Delete(detached(c), slugKeys...)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Post permanently deleted",
+		"id":      post.ID,
+	})
+}
+
+// findTrashedPost loads the soft-deleted post addressed by :id and checks the
+// caller may perform action on it, writing an error response and returning
+// false otherwise
+func (h *Handler) findTrashedPost(c *gin.Context, action policy.Action) (models.Post, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return models.Post{}, false
+	}
+
+	var post models.Post
+	if err := h.db(c).Unscoped().Where("deleted_at IS NOT NULL").First(&post, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found in trash"})
+		return models.Post{}, false
+	}
+
+	if !authorize(c, action, &post) {
+		return models.Post{}, false
+	}
+
+	return post, true
+}
@@ -0,0 +1,160 @@
+package health
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/olivere/elastic/v7"
+	"github.com/susbuntu/blog-api/breaker"
+	"gorm.io/gorm"
+)
+
+// Overall readiness states
+const (
+	// StatusReady means every dependency answered
+	StatusReady = "ready"
+	// StatusDegraded means an optional dependency is down or behind an open
+	// circuit breaker; the API still serves, without it
+	StatusDegraded = "degraded"
+	// StatusUnavailable means a required dependency is down
+	StatusUnavailable = "unavailable"
+)
+
+// Check probes one dependency, returning the version it reports
+type Check struct {
+	Name  string
+	Probe func(ctx context.Context) (version string, err error)
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Name          string  `json:"name" example:"postgres"`
+	Status        string  `json:"status" example:"up" enums:"up,down"`
+	Required      bool    `json:"required" example:"true"`
+	LatencyMillis float64 `json:"latency_ms" example:"1.42"`
+	Version       string  `json:"version,omitempty" example:"15.4"`
+	Error         string  `json:"error,omitempty" example:"context deadline exceeded"`
+}
+
+// Report is the readiness of the API and each of its dependencies
+type Report struct {
+	Status   string           `json:"status" example:"ready" enums:"ready,degraded,unavailable"`
+	Checks   []Result         `json:"checks"`
+	Breakers []breaker.Status `json:"breakers"`
+}
+
+// Ready reports whether the API should receive traffic
+func (r Report) Ready() bool {
+	return r.Status != StatusUnavailable
+}
+
+// Run probes every dependency at once, each bounded by timeout. The API is
+// unavailable if a check named in required fails, and degraded if another
+// check fails or a circuit breaker isn't closed.
+func Run(ctx context.Context, checks []Check, required []string, timeout time.Duration, breakers []*breaker.Breaker) Report {
+	report := Report{
+		Status:   StatusReady,
+		Checks:   make([]Result, len(checks)),
+		Breakers: make([]breaker.Status, 0, len(breakers)),
+	}
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			report.Checks[i] = run(ctx, check, timeout)
+		}(i, check)
+	}
+	wg.Wait()
+
+	for i := range report.Checks {
+		result := &report.Checks[i]
+		result.Required = contains(required, result.Name)
+		if result.Status == "up" {
+			continue
+		}
+		if result.Required {
+			report.Status = StatusUnavailable
+		} else if report.Status == StatusReady {
+			report.Status = StatusDegraded
+		}
+	}
+
+	for _, b := range breakers {
+		status := b.Status()
+		if status.State != breaker.Closed && report.Status == StatusReady {
+			report.Status = StatusDegraded
+		}
+		report.Breakers = append(report.Breakers, status)
+	}
+
+	return report
+}
+
+func run(ctx context.Context, check Check, timeout time.Duration) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	started := time.Now()
+	version, err := check.Probe(ctx)
+	result := Result{
+		Name:          check.Name,
+		Status:        "up",
+		LatencyMillis: float64(time.Since(started).Microseconds()) / 1000,
+		Version:       version,
+	}
+	if err != nil {
+		result.Status = "down"
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Postgres checks the database answers and reports its server version
+func Postgres(db *gorm.DB) Check {
+	return Check{Name: "postgres", Probe: func(ctx context.Context) (string, error) {
+		var version string
+		err := db.WithContext(ctx).Raw("SHOW server_version").Scan(&version).Error
+		return version, err
+	}}
+}
+
+// Redis checks Redis answers and reports its server version
+func Redis(client *redis.Client) Check {
+	return Check{Name: "redis", Probe: func(ctx context.Context) (string, error) {
+		info, err := client.Info(ctx, "server").Result()
+		if err != nil {
+			return "", err
+		}
+		for _, line := range strings.Split(info, "\n") {
+			if version, ok := strings.CutPrefix(strings.TrimSpace(line), "redis_version:"); ok {
+				return version, nil
+			}
+		}
+		return "", nil
+	}}
+}
+
+// Elasticsearch checks the cluster at url answers and reports its version
+func Elasticsearch(client *elastic.Client, url string) Check {
+	return Check{Name: "elasticsearch", Probe: func(ctx context.Context) (string, error) {
+		res, _, err := client.Ping(url).Do(ctx)
+		if err != nil {
+			return "", err
+		}
+		return res.Version.Number, nil
+	}}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -1,23 +0,0 @@
--- Create posts table
-CREATE TABLE IF NOT EXISTS posts (
-    id SERIAL PRIMARY KEY,
-    title VARCHAR(255) NOT NULL,
-    content TEXT NOT NULL,
-    tags TEXT[] DEFAULT '{}',
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-);
-
--- Create activity_logs table
-CREATE TABLE IF NOT EXISTS activity_logs (
-    id SERIAL PRIMARY KEY,
-    action VARCHAR(100) NOT NULL,
-    post_id INTEGER REFERENCES posts(id),
-    logged_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-);
-
--- Create GIN index on tags for faster search
-CREATE INDEX IF NOT EXISTS idx_posts_tags ON posts USING GIN(tags);
-
--- Create index on created_at for better sorting performance
-CREATE INDEX IF NOT EXISTS idx_posts_created_at ON posts(created_at DESC);
@@ -14,44 +14,99 @@
 // @BasePath /api/v1
 
 // @schemes http
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Type "Bearer" followed by a space and the access token, or "ApiKey" followed by a space and an API key.
 package main
 
 import (
+	"fmt"
 	"log"
+	"os"
+	"strings"
 
-	"github.com/gin-gonic/gin"
 	"github.com/susbuntu/blog-api/config"
 	"github.com/susbuntu/blog-api/database"
-	"github.com/susbuntu/blog-api/routes"
+	"gorm.io/gorm"
 
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
 	_ "github.com/susbuntu/blog-api/docs" // This will be auto-generated
 )
 
+// command is a CLI subcommand. Every command shares config.Load and opens the
+// connections it needs through the database.Init* constructors.
+type command struct {
+	usage   string
+	summary string
+	run     func(cfg *config.Config, args []string) error
+}
+
+var commands = map[string]command{
+	"serve":   {"serve", "Run the API server and background workers (default)", runServe},
+	"migrate": {"migrate up | down [steps] | status", "Apply, revert or list database migrations", runMigrate},
+	"reindex": {"reindex", "Rebuild the Elasticsearch posts index from Postgres without downtime", runReindex},
+	"seed":    {"seed [-password <password>]", "Create a demo author and sample posts", runSeed},
+	"user":    {"user create -email <email> [-name <name>] [-role <role>] [-password <password>]", "Create a user account", runUser},
+	"cache":   {"cache flush", "Delete everything the API has cached in Redis", runCache},
+}
+
+// commandOrder is the order commands are listed in the usage message
+var commandOrder = []string{"serve", "migrate", "reindex", "seed", "user", "cache"}
+
 func main() {
 	// Load configuration
 	cfg := config.Load()
 
-	// Initialize database connections
-	db := database.InitPostgreSQL(cfg)
-	redis := database.InitRedis(cfg)
-	es := database.InitElasticsearch(cfg)
+	name, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" || name == "-h" || name == "--help" {
+		fmt.Print(usage())
+		return
+	}
 
-	// Auto migrate database
-	database.AutoMigrate(db)
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", name, usage())
+		os.Exit(2)
+	}
+	if err := cmd.run(cfg, args); err != nil {
+		log.Fatalf("%s: %v", name, err)
+	}
+}
 
-	// Initialize Gin router
-	router := gin.Default()
+func usage() string {
+	var b strings.Builder
+	b.WriteString("Usage: blog-api <command> [arguments]\n\nCommands:\n")
+	for _, name := range commandOrder {
+		cmd := commands[name]
+		fmt.Fprintf(&b, "  %s\n      %s\n", cmd.usage, cmd.summary)
+	}
+	return b.String()
+}
 
-	// Setup routes
-	routes.SetupRoutes(router, db, redis, es)
+// openMigratedDB connects to Postgres and makes sure the schema is current.
+// Pending migrations are applied if DB_MIGRATE_ON_START is set; otherwise the
+// command refuses to run, since production deploys run "migrate up" ahead of
+// rolling out new replicas.
+func openMigratedDB(cfg *config.Config) (*gorm.DB, error) {
+	db := database.InitPostgreSQL(cfg)
 
-	// Swagger endpoint
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	if cfg.Database.MigrateOnStart {
+		if _, err := database.MigrateUp(db); err != nil {
+			return nil, fmt.Errorf("migrate database: %w", err)
+		}
+		return db, nil
+	}
 
-	// Start server
-	log.Printf("Server starting on port %s", cfg.Port)
-	log.Printf("Swagger documentation available at: http://localhost:%s/swagger/index.html", cfg.Port)
-	log.Fatal(router.Run(":" + cfg.Port))
+	pending, err := database.PendingMigrations(db)
+	if err != nil {
+		return nil, fmt.Errorf("check migrations: %w", err)
+	}
+	if len(pending) > 0 {
+		return nil, fmt.Errorf("database has %d pending migrations; run \"migrate up\" or set DB_MIGRATE_ON_START=true", len(pending))
+	}
+	return db, nil
 }
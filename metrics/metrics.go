@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/susbuntu/blog-api/models"
+)
+
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "blog_http_requests_total",
+		Help: "HTTP requests served, by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "blog_http_request_duration_seconds",
+		Help:    "How long HTTP requests took to serve, by method, route and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// PostCacheLookups counts the cache-aside reads of single posts
+	PostCacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "blog_post_cache_lookups_total",
+		Help: "Post cache lookups, by result (hit or miss).",
+	}, []string{"result"})
+
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "blog_db_query_duration_seconds",
+		Help:    "How long database queries took, by operation and table.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	// SearchDuration is measured by the API, so it includes the round trip to
+	// the search backend
+	SearchDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "blog_search_duration_seconds",
+		Help:    "How long full-text searches took as seen by the API, by backend.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"backend"})
+
+	// SearchTook is the time the search backend reports spending on the query
+	SearchTook = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "blog_search_took_milliseconds",
+		Help:    "Time the search backend reported spending on full-text searches, by backend.",
+		Buckets: []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500},
+	}, []string{"backend"})
+
+	SearchSyncFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "blog_search_sync_failures_total",
+		Help: "Failed attempts to update the search index in the background, by operation (index or delete).",
+	}, []string{"operation"})
+
+	// SearchDrift is the drift found by the last consistency check on this
+	// replica. Replicas that haven't run a check don't report it.
+	SearchDrift = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "blog_search_drift_documents",
+		Help: "Posts missing from the search index, stale in it, or orphaned documents, as of the last consistency check.",
+	}, []string{"kind"})
+
+	SearchConsistencyChecks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "blog_search_consistency_checks_total",
+		Help: "Search index consistency checks run, by result.",
+	}, []string{"result"})
+
+	SearchConsistencyLastCheck = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "blog_search_consistency_last_check_timestamp_seconds",
+		Help: "When the last successful consistency check finished.",
+	})
+
+	SearchConsistencyDuration = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "blog_search_consistency_check_duration_seconds",
+		Help: "How long the last successful consistency check took.",
+	})
+)
+
+// Handler serves the registered metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RecordRequest records a served HTTP request. route is the matched route
+// pattern rather than the raw path, so IDs and slugs don't each get a series.
+func RecordRequest(method, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	HTTPRequests.WithLabelValues(method, route, code).Inc()
+	HTTPRequestDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+// RecordPostCacheLookup records whether a post was served from the cache
+func RecordPostCacheLookup(hit bool) {
+	if hit {
+		PostCacheLookups.WithLabelValues("hit").Inc()
+		return
+	}
+	PostCacheLookups.WithLabelValues("miss").Inc()
+}
+
+// RecordSearch records a full-text search answered by backend
+func RecordSearch(backend string, duration time.Duration, tookInMillis int64) {
+	SearchDuration.WithLabelValues(backend).Observe(duration.Seconds())
+	SearchTook.WithLabelValues(backend).Observe(float64(tookInMillis))
+}
+
+// RecordConsistencyCheck publishes the outcome of a consistency check. check
+// is nil when the check failed.
+func RecordConsistencyCheck(check *models.ConsistencyCheck) {
+	if check == nil {
+		SearchConsistencyChecks.WithLabelValues("failed").Inc()
+		return
+	}
+
+	SearchConsistencyChecks.WithLabelValues("ok").Inc()
+	SearchDrift.WithLabelValues("missing").Set(float64(check.Missing))
+	SearchDrift.WithLabelValues("stale").Set(float64(check.Stale))
+	SearchDrift.WithLabelValues("orphaned").Set(float64(check.Orphaned))
+	SearchConsistencyLastCheck.Set(float64(time.Now().Unix()))
+	SearchConsistencyDuration.Set(check.Duration.Seconds())
+}
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/susbuntu/blog-api/auth"
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/repository"
+	"gorm.io/gorm"
+)
+
+// lastUsedGranularity limits how often an API key's last_used_at is written
+const lastUsedGranularity = time.Minute
+
+// RequireAuth rejects requests that don't carry a valid "Authorization: Bearer <token>"
+// or "Authorization: ApiKey <key>" header. The user is loaded on every request so
+// role changes take effect immediately. API keys live in Postgres, so they are
+// rejected when db is nil.
+func RequireAuth(tokens *auth.TokenManager, users repository.UserRepository, db *gorm.DB) gin.HandlerFunc {
+	return authenticate(tokens, users, db, true)
+}
+
+// OptionalAuth authenticates the caller when an Authorization header is present and
+// lets anonymous requests through. Bad credentials are still rejected rather than
+// silently treated as anonymous.
+func OptionalAuth(tokens *auth.TokenManager, users repository.UserRepository, db *gorm.DB) gin.HandlerFunc {
+	return authenticate(tokens, users, db, false)
+}
+
+func authenticate(tokens *auth.TokenManager, users repository.UserRepository, db *gorm.DB, required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" && !required {
+			c.Next()
+			return
+		}
+
+		scheme, credential, found := strings.Cut(header, " ")
+		if !found || credential == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Missing or malformed Authorization header"})
+			return
+		}
+
+		var (
+			principal auth.Principal
+			ok        bool
+		)
+		switch {
+		case strings.EqualFold(scheme, "Bearer"):
+			principal, ok = authenticateToken(c.Request.Context(), users, tokens, credential)
+		case strings.EqualFold(scheme, "ApiKey") && db != nil:
+			principal, ok = authenticateAPIKey(db.WithContext(c.Request.Context()), credential)
+		default:
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unsupported Authorization scheme"})
+			return
+		}
+
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired credentials"})
+			return
+		}
+
+		auth.SetPrincipal(c, principal)
+		c.Next()
+	}
+}
+
+func authenticateToken(ctx context.Context, users repository.UserRepository, tokens *auth.TokenManager, token string) (auth.Principal, bool) {
+	claims, err := tokens.Parse(token, auth.AccessToken)
+	if err != nil {
+		return auth.Principal{}, false
+	}
+
+	user, err := users.Find(ctx, claims.UserID)
+	if err != nil {
+		return auth.Principal{}, false
+	}
+
+	return auth.Principal{UserID: user.ID, Role: user.Role}, true
+}
+
+func authenticateAPIKey(db *gorm.DB, key string) (auth.Principal, bool) {
+	var apiKey models.APIKey
+	if err := db.Where("key_hash = ? AND revoked_at IS NULL", auth.HashAPIKey(key)).First(&apiKey).Error; err != nil {
+		return auth.Principal{}, false
+	}
+
+	now := time.Now()
+	if apiKey.ExpiresAt != nil && now.After(*apiKey.ExpiresAt) {
+		return auth.Principal{}, false
+	}
+
+	var user models.User
+	if err := db.Select("id", "role").First(&user, apiKey.UserID).Error; err != nil {
+		return auth.Principal{}, false
+	}
+
+	// Track usage without turning every request into a write
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > lastUsedGranularity {
+		db.Model(&apiKey).UpdateColumn("last_used_at", now)
+	}
+
+	return auth.Principal{
+		UserID:   user.ID,
+		Role:     user.Role,
+		APIKeyID: &apiKey.ID,
+		Scopes:   []string(apiKey.Scopes),
+	}, true
+}
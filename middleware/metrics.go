@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/susbuntu/blog-api/metrics"
+)
+
+// Metrics records every request's count and latency under its route pattern.
+// Requests that match no route are grouped together.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		started := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.RecordRequest(c.Request.Method, route, c.Writer.Status(), time.Since(started))
+	}
+}
@@ -2,10 +2,13 @@ package models
 
 import (
 	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/lib/pq"
+	"gorm.io/gorm"
 )
 
 // StringArray is a custom type for PostgreSQL text arrays that works with Swagger
@@ -32,45 +35,442 @@ func (s StringArray) String() string {
 	return strings.Join(s, ",")
 }
 
+// IDArray is a custom type for PostgreSQL bigint arrays of record IDs
+type IDArray []uint
+
+// Value implements driver.Valuer interface
+func (a IDArray) Value() (driver.Value, error) {
+	ints := make(pq.Int64Array, len(a))
+	for i, id := range a {
+		ints[i] = int64(id)
+	}
+	return ints.Value()
+}
+
+// Scan implements sql.Scanner interface
+func (a *IDArray) Scan(value interface{}) error {
+	var ints pq.Int64Array
+	if err := ints.Scan(value); err != nil {
+		return err
+	}
+	ids := make(IDArray, len(ints))
+	for i, n := range ints {
+		ids[i] = uint(n)
+	}
+	*a = ids
+	return nil
+}
+
+// User roles, from least to most privileged
+const (
+	RoleAuthor = "author"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// User represents an account that can author posts
+type User struct {
+	ID           uint      `json:"id" gorm:"primaryKey" example:"1"`
+	Email        string    `json:"email" gorm:"uniqueIndex;not null" example:"jane@example.com"`
+	Name         string    `json:"name" example:"Jane Doe"`
+	Role         string    `json:"role" gorm:"not null;default:author" example:"author"`
+	PasswordHash string    `json:"-" gorm:"not null"`
+	CreatedAt    time.Time `json:"created_at" example:"2023-09-14T08:04:38.522445Z"`
+	UpdatedAt    time.Time `json:"updated_at" example:"2023-09-14T08:04:38.522445Z"`
+}
+
+// APIKey represents a scoped credential for machine clients. Only the hash of
+// the key is stored; the plaintext is shown once when the key is created.
+type APIKey struct {
+	ID         uint        `json:"id" gorm:"primaryKey" example:"1"`
+	Name       string      `json:"name" gorm:"not null" example:"CI importer"`
+	Prefix     string      `json:"prefix" gorm:"not null" example:"blog_3f9a1c2e"`
+	KeyHash    string      `json:"-" gorm:"uniqueIndex;not null"`
+	Scopes     StringArray `json:"scopes" gorm:"type:text[]" swaggertype:"array,string" example:"posts:read,posts:write"`
+	UserID     uint        `json:"user_id" gorm:"index;not null" example:"1"`
+	ExpiresAt  *time.Time  `json:"expires_at" example:"2024-09-14T08:04:38.522445Z"`
+	LastUsedAt *time.Time  `json:"last_used_at" example:"2023-09-15T10:00:00Z"`
+	RevokedAt  *time.Time  `json:"revoked_at" example:"2023-09-16T10:00:00Z"`
+	CreatedAt  time.Time   `json:"created_at" example:"2023-09-14T08:04:38.522445Z"`
+}
+
+// Post lifecycle states. New posts start as drafts; the status column defaults to
+// published so posts that were live before statuses existed stay visible.
+const (
+	PostDraft     = "draft"
+	PostPublished = "published"
+	PostArchived  = "archived"
+)
+
 // Post represents a blog post
 type Post struct {
-	ID        uint        `json:"id" gorm:"primaryKey" example:"1"`
-	Title     string      `json:"title" gorm:"not null" example:"My First Blog Post"`
-	Content   string      `json:"content" gorm:"type:text;not null" example:"This is the content of my first blog post."`
-	Tags      StringArray `json:"tags" gorm:"type:text[]" swaggertype:"array,string" example:"golang,programming,tutorial"`
-	CreatedAt time.Time   `json:"created_at" example:"2023-09-14T08:04:38.522445Z"`
-	UpdatedAt time.Time   `json:"updated_at" example:"2023-09-14T08:04:38.522445Z"`
+	ID            uint           `json:"id" gorm:"primaryKey" example:"1"`
+	Title         string         `json:"title" gorm:"not null" example:"My First Blog Post"`
+	Slug          string         `json:"slug" gorm:"uniqueIndex" example:"my-first-blog-post"` // Old slugs live on in post_slugs as redirects
+	Content       string         `json:"content" gorm:"type:text;not null" example:"This is the content of my first blog post."`
+	ContentFormat string         `json:"content_format" gorm:"not null;default:markdown" example:"markdown"`
+	ContentHTML   string         `json:"content_html" gorm:"-" example:"<p>This is the content of my first blog post.</p>"` // Rendered and sanitized from Content; cached with the post
+	Tags          StringArray    `json:"tags" gorm:"type:text[]" swaggertype:"array,string" example:"golang,programming,tutorial"`
+	AuthorID      *uint          `json:"author_id" gorm:"index" example:"1"` // Pointer because posts created before accounts existed have no author
+	Status        string         `json:"status" gorm:"not null;default:published;index" example:"published"`
+	PublishedAt   *time.Time     `json:"published_at" example:"2023-09-14T09:00:00Z"`
+	ScheduledFor  *time.Time     `json:"scheduled_for" gorm:"index" example:"2023-09-20T09:00:00Z"` // Drafts with this set are published by the scheduler once it passes
+	CreatedAt     time.Time      `json:"created_at" example:"2023-09-14T08:04:38.522445Z"`
+	UpdatedAt     time.Time      `json:"updated_at" example:"2023-09-14T08:04:38.522445Z"`
+	DeletedAt     gorm.DeletedAt `json:"deleted_at" gorm:"index" swaggertype:"string" example:"2023-09-15T10:00:00Z"` // Set while the post is in the trash
+	Media         []PostMedia    `json:"media,omitempty" gorm:"foreignKey:PostID"`                                    // Cover and inline media, loaded for single-post reads and listings
+}
+
+// OwnedBy reports whether the post was written by the given user
+func (p *Post) OwnedBy(userID uint) bool {
+	return p.AuthorID != nil && *p.AuthorID == userID
+}
+
+// PostVisibility describes which posts a reader may see. Published posts are
+// visible to everyone.
+type PostVisibility struct {
+	// All shows posts in every state
+	All bool
+	// AuthorID also shows the unpublished posts of this author
+	AuthorID *uint
+}
+
+// Allows reports whether the post is visible
+func (v PostVisibility) Allows(post *Post) bool {
+	return v.All || post.Status == PostPublished || (v.AuthorID != nil && post.OwnedBy(*v.AuthorID))
+}
+
+// PostSlug is a slug a post used before its title changed. Lookups by an old
+// slug redirect to the post's current one.
+type PostSlug struct {
+	ID        uint      `json:"id" gorm:"primaryKey" example:"1"`
+	Slug      string    `json:"slug" gorm:"not null;uniqueIndex" example:"my-first-post"`
+	PostID    uint      `json:"post_id" gorm:"not null;index" example:"1"`
+	CreatedAt time.Time `json:"created_at" example:"2023-09-14T08:04:38.522445Z"`
+}
+
+// PostRevision is a snapshot of a post's editable fields. A revision is written
+// whenever a post is created, updated or restored, numbered per post from 1.
+type PostRevision struct {
+	ID            uint        `json:"id" gorm:"primaryKey" example:"1"`
+	PostID        uint        `json:"post_id" gorm:"not null;uniqueIndex:idx_post_revisions_post_revision" example:"1"`
+	Revision      int         `json:"revision" gorm:"not null;uniqueIndex:idx_post_revisions_post_revision" example:"2"`
+	Title         string      `json:"title" gorm:"not null" example:"My First Blog Post"`
+	Content       string      `json:"content" gorm:"type:text;not null" example:"This is the content of my first blog post."`
+	ContentFormat string      `json:"content_format" gorm:"not null;default:markdown" example:"markdown"`
+	Tags          StringArray `json:"tags" gorm:"type:text[]" swaggertype:"array,string" example:"golang,programming,tutorial"`
+	EditorID      *uint       `json:"editor_id" example:"1"`
+	RestoredFrom  *int        `json:"restored_from,omitempty" example:"1"` // Revision number this one was restored from
+	CreatedAt     time.Time   `json:"created_at" example:"2023-09-14T08:04:38.522445Z"`
+}
+
+// Comment moderation states
+const (
+	CommentPending  = "pending"
+	CommentApproved = "approved"
+	CommentRejected = "rejected"
+	CommentSpam     = "spam"
+)
+
+// Comment represents a comment on a post. Replies point at their parent comment.
+// Only approved comments are shown to readers.
+type Comment struct {
+	ID          uint        `json:"id" gorm:"primaryKey" example:"1"`
+	PostID      uint        `json:"post_id" gorm:"index;not null" example:"1"`
+	ParentID    *uint       `json:"parent_id" gorm:"index" example:"1"`
+	AuthorID    uint        `json:"author_id" gorm:"index;not null" example:"1"`
+	Body        string      `json:"body" gorm:"type:text;not null" example:"Great post, thanks for sharing!"`
+	Status      string      `json:"status" gorm:"not null;default:pending;index" example:"approved"`
+	SpamScore   float64     `json:"spam_score" gorm:"not null;default:0" example:"0.1"`
+	SpamReasons StringArray `json:"spam_reasons,omitempty" gorm:"type:text[]" swaggertype:"array,string" example:"too many links"`
+	CreatedAt   time.Time   `json:"created_at" example:"2023-09-14T08:04:38.522445Z"`
+	UpdatedAt   time.Time   `json:"updated_at" example:"2023-09-14T08:04:38.522445Z"`
+
+	// Depth is the nesting level within the thread, filled in by thread queries
+	Depth   int       `json:"depth" gorm:"->;-:migration" example:"0"`
+	Replies []Comment `json:"replies,omitempty" gorm:"-"`
+}
+
+// OwnedBy reports whether the comment was written by the given user
+func (c *Comment) OwnedBy(userID uint) bool {
+	return c.AuthorID == userID
+}
+
+// Media is an uploaded file. The content lives in the blob store under
+// StorageKey; MIMEType is sniffed from the content rather than trusted from the client.
+type Media struct {
+	ID         uint          `json:"id" gorm:"primaryKey" example:"1"`
+	OwnerID    uint          `json:"owner_id" gorm:"index;not null" example:"1"`
+	Filename   string        `json:"filename" example:"cover.png"`
+	MIMEType   string        `json:"mime_type" gorm:"not null" example:"image/png"`
+	Size       int64         `json:"size" gorm:"not null" example:"48213"`
+	Checksum   string        `json:"checksum" gorm:"index;not null" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"` // Hex SHA-256 of the content
+	StorageKey string        `json:"-" gorm:"uniqueIndex;not null"`
+	URL        string        `json:"url" gorm:"-" example:"/api/v1/media/1/content"`
+	Width      int           `json:"width,omitempty" example:"2400"` // Filled in by the thumbnail worker for images
+	Height     int           `json:"height,omitempty" example:"1600"`
+	Thumbnails string        `json:"thumbnails" gorm:"not null;default:pending;index" example:"ready"`
+	Variants   MediaVariants `json:"variants" gorm:"type:jsonb" swaggertype:"array,object"`
+	// Srcset holds a ready-made srcset attribute per variant MIME type
+	Srcset    map[string]string `json:"srcset,omitempty" gorm:"-"`
+	CreatedAt time.Time         `json:"created_at" example:"2023-09-14T08:04:38.522445Z"`
+}
+
+// Thumbnail generation states for media
+const (
+	ThumbnailsPending = "pending"
+	ThumbnailsReady   = "ready"
+	ThumbnailsSkipped = "skipped" // Not an image that can be resized
+	ThumbnailsFailed  = "failed"
+)
+
+// MediaVariant is a resized copy of an image. Its blob is stored next to the
+// original under the original's storage key plus "." and Name.
+type MediaVariant struct {
+	Name     string `json:"name" example:"640w.webp"`
+	Width    int    `json:"width" example:"640"`
+	Height   int    `json:"height" example:"427"`
+	MIMEType string `json:"mime_type" example:"image/webp"`
+	Size     int64  `json:"size" example:"18342"`
+	URL      string `json:"url,omitempty" example:"/api/v1/media/1/variants/640w.webp"`
+}
+
+// MediaVariants is stored as a JSON array
+type MediaVariants []MediaVariant
+
+// Value implements driver.Valuer interface
+func (v MediaVariants) Value() (driver.Value, error) {
+	if v == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// Scan implements sql.Scanner interface
+func (v *MediaVariants) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(data, v)
+	case string:
+		return json.Unmarshal([]byte(data), v)
+	default:
+		return fmt.Errorf("cannot scan %T into MediaVariants", value)
+	}
+}
+
+// OwnedBy reports whether the media was uploaded by the given user
+func (m *Media) OwnedBy(userID uint) bool {
+	return m.OwnerID == userID
+}
+
+// Roles media can play on a post. A post has at most one cover.
+const (
+	MediaCover  = "cover"
+	MediaInline = "inline"
+)
+
+// PostMedia attaches media to a post
+type PostMedia struct {
+	PostID    uint      `json:"post_id" gorm:"primaryKey" example:"1"`
+	MediaID   uint      `json:"media_id" gorm:"primaryKey;index" example:"1"`
+	Role      string    `json:"role" gorm:"not null" example:"inline"`
+	Media     Media     `json:"media" gorm:"foreignKey:MediaID"`
+	CreatedAt time.Time `json:"created_at" example:"2023-09-14T08:04:38.522445Z"`
 }
 
 // ActivityLog represents system activity logs
 type ActivityLog struct {
-	ID       uint      `json:"id" gorm:"primaryKey" example:"1"`
-	Action   string    `json:"action" gorm:"not null" example:"new_post"`
-	PostID   *uint     `json:"post_id" example:"1"` // Changed to pointer to allow NULL values
-	Post     Post      `json:"post" gorm:"foreignKey:PostID"`
-	LoggedAt time.Time `json:"logged_at" example:"2023-09-14T08:04:38.522445Z"`
+	ID        uint      `json:"id" gorm:"primaryKey" example:"1"`
+	Action    string    `json:"action" gorm:"not null" example:"new_post"`
+	PostID    *uint     `json:"post_id" example:"1"` // Not a foreign key so entries keep the ID of a purged post
+	Post      Post      `json:"post" gorm:"foreignKey:PostID"`
+	CommentID *uint     `json:"comment_id" example:"1"` // Not a foreign key so the log survives the comment being deleted
+	LoggedAt  time.Time `json:"logged_at" example:"2023-09-14T08:04:38.522445Z"`
+}
+
+// Outbox event kinds. Both are delivered by syncing the post's current state,
+// so the kind only records why the event was written.
+const (
+	OutboxIndexPost  = "index_post"
+	OutboxDeletePost = "delete_post"
+)
+
+// Outbox event states. Delivered events are deleted, so only undelivered ones remain.
+const (
+	OutboxPending = "pending"
+	OutboxDead    = "dead" // Gave up after too many failed attempts; can be replayed
+)
+
+// OutboxEvent records a search index change in the same transaction as the
+// post change that caused it. The outbox relay delivers it to Elasticsearch.
+type OutboxEvent struct {
+	ID            uint      `json:"id" gorm:"primaryKey" example:"1"`
+	Kind          string    `json:"kind" gorm:"not null" example:"index_post"`
+	PostID        uint      `json:"post_id" gorm:"not null;index" example:"1"` // Not a foreign key so delete events survive a purge
+	Status        string    `json:"status" gorm:"not null;default:pending" example:"dead"`
+	Attempts      int       `json:"attempts" gorm:"not null;default:0" example:"10"`
+	LastError     string    `json:"last_error,omitempty" example:"no available connection"`
+	NextAttemptAt time.Time `json:"next_attempt_at" gorm:"not null" example:"2023-09-14T08:05:38Z"`
+	TraceParent   string    `json:"trace_parent,omitempty" example:"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"` // W3C traceparent of the request that queued the event
+	CreatedAt     time.Time `json:"created_at" example:"2023-09-14T08:04:38.522445Z"`
+	UpdatedAt     time.Time `json:"updated_at" example:"2023-09-14T09:04:38.522445Z"`
+}
+
+// ConsistencyCheck is the outcome of comparing the search index with Postgres.
+// Posts with undelivered outbox events are skipped, since they are still
+// being synced.
+type ConsistencyCheck struct {
+	ID        uint          `json:"id" gorm:"primaryKey" example:"1"`
+	StartedAt time.Time     `json:"started_at" gorm:"not null" example:"2023-09-14T08:00:00Z"`
+	Duration  time.Duration `json:"duration" gorm:"not null" swaggertype:"integer" example:"5230000000"`
+	Posts     int           `json:"posts" gorm:"not null" example:"1250"`     // Live posts compared
+	Documents int           `json:"documents" gorm:"not null" example:"1251"` // Documents in the index
+	// Missing posts have no document, stale ones a document that doesn't match
+	// the post, and orphaned documents belong to no live post
+	Missing  int `json:"missing" gorm:"not null" example:"2"`
+	Stale    int `json:"stale" gorm:"not null" example:"1"`
+	Orphaned int `json:"orphaned" gorm:"not null" example:"3"`
+	// The first IDs of each kind of drift
+	MissingIDs  IDArray `json:"missing_ids" gorm:"type:bigint[]" swaggertype:"array,integer" example:"17,42"`
+	StaleIDs    IDArray `json:"stale_ids" gorm:"type:bigint[]" swaggertype:"array,integer" example:"8"`
+	OrphanedIDs IDArray `json:"orphaned_ids" gorm:"type:bigint[]" swaggertype:"array,integer" example:"3,4,5"`
+	// Repaired is true when fixes for the drift were queued on the outbox
+	Repaired  bool      `json:"repaired" gorm:"not null;default:false" example:"false"`
+	CreatedAt time.Time `json:"created_at" example:"2023-09-14T08:00:05.230Z"`
 }
 
 // PostSearchResult represents the structure for Elasticsearch documents
 type PostSearchResult struct {
-	ID      uint     `json:"id" example:"1"`
-	Title   string   `json:"title" example:"My First Blog Post"`
-	Content string   `json:"content" example:"This is the content of my first blog post."`
-	Tags    []string `json:"tags" example:"golang,programming,tutorial"`
+	ID            uint       `json:"id" example:"1"`
+	Title         string     `json:"title" example:"My First Blog Post"`
+	Slug          string     `json:"slug" example:"my-first-blog-post"`
+	Content       string     `json:"content" example:"This is the content of my first blog post."`
+	ContentFormat string     `json:"content_format" example:"markdown"`
+	ContentHTML   string     `json:"content_html,omitempty" example:"<p>This is the content of my first blog post.</p>"` // Rendered from Content in search responses; not indexed
+	Tags          []string   `json:"tags" example:"golang,programming,tutorial"`
+	AuthorID      *uint      `json:"author_id" example:"1"`
+	Status        string     `json:"status" example:"published"`
+	PublishedAt   *time.Time `json:"published_at" example:"2023-09-14T09:00:00Z"`
 }
 
 // CreatePostRequest represents the request body for creating a post
 type CreatePostRequest struct {
-	Title   string   `json:"title" binding:"required" example:"My First Blog Post"`
-	Content string   `json:"content" binding:"required" example:"This is the content of my first blog post."`
-	Tags    []string `json:"tags" example:"golang,programming,tutorial"`
+	Title         string   `json:"title" binding:"required" example:"My First Blog Post"`
+	Content       string   `json:"content" binding:"required" example:"This is the content of my first blog post."`
+	ContentFormat string   `json:"content_format" binding:"omitempty,oneof=markdown html plain" example:"markdown"` // Defaults to markdown
+	Tags          []string `json:"tags" example:"golang,programming,tutorial"`
 }
 
 // UpdatePostRequest represents the request body for updating a post
 type UpdatePostRequest struct {
-	Title   string   `json:"title" example:"Updated Blog Post Title"`
-	Content string   `json:"content" example:"Updated content of the blog post."`
-	Tags    []string `json:"tags" example:"golang,programming,updated"`
+	Title         string   `json:"title" example:"Updated Blog Post Title"`
+	Content       string   `json:"content" example:"Updated content of the blog post."`
+	ContentFormat string   `json:"content_format" binding:"omitempty,oneof=markdown html plain" example:"markdown"`
+	Tags          []string `json:"tags" example:"golang,programming,updated"`
+}
+
+// RegisterRequest represents the request body for creating a user account
+type RegisterRequest struct {
+	Email    string `json:"email" binding:"required,email" example:"jane@example.com"`
+	Name     string `json:"name" example:"Jane Doe"`
+	Password string `json:"password" binding:"required,min=8" example:"correct-horse-battery"`
+}
+
+// LoginRequest represents the request body for logging in
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,email" example:"jane@example.com"`
+	Password string `json:"password" binding:"required" example:"correct-horse-battery"`
+}
+
+// RefreshRequest represents the request body for refreshing an access token
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+}
+
+// AuthResponse represents the tokens issued after registering, logging in or refreshing
+type AuthResponse struct {
+	AccessToken  string `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken string `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TokenType    string `json:"token_type" example:"Bearer"`
+	ExpiresIn    int    `json:"expires_in" example:"900"`
+	User         User   `json:"user"`
+}
+
+// UpdateUserRoleRequest represents the request body for changing a user's role
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=author editor admin" example:"editor"`
+}
+
+// UsersResponse represents the response for listing users with pagination
+type UsersResponse struct {
+	Users      []User             `json:"users"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+// CreateAPIKeyRequest represents the request body for creating an API key
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required" example:"CI importer"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,oneof=posts:read posts:write logs:read" example:"posts:read,posts:write"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2024-09-14T08:04:38Z"`
+}
+
+// CreateAPIKeyResponse includes the plaintext key, which is never shown again
+type CreateAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key" example:"blog_3f9a1c2e..."`
+}
+
+// CreateCommentRequest represents the request body for creating a comment
+type CreateCommentRequest struct {
+	Body     string `json:"body" binding:"required,max=10000" example:"Great post, thanks for sharing!"`
+	ParentID *uint  `json:"parent_id" example:"1"`
+}
+
+// UpdateCommentRequest represents the request body for editing a comment
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required,max=10000" example:"Great post, thanks for sharing! (edited)"`
+}
+
+// ModerateCommentsRequest represents a bulk moderation decision
+type ModerateCommentsRequest struct {
+	IDs    []uint `json:"ids" binding:"required,min=1,max=100" example:"1,2,3"`
+	Action string `json:"action" binding:"required,oneof=approve reject spam" example:"approve"`
+}
+
+// ModerateCommentsResponse reports which comments a bulk moderation decision was applied to
+type ModerateCommentsResponse struct {
+	Status  string `json:"status" example:"approved"`
+	Updated []uint `json:"updated" example:"1,2"`
+}
+
+// CommentsResponse represents the response for listing comments with pagination.
+// In tree view pagination counts top-level comments; in flat view it counts all comments.
+type CommentsResponse struct {
+	Comments   []Comment          `json:"comments"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+// SchedulePostRequest represents the request body for scheduling a draft to be published
+type SchedulePostRequest struct {
+	ScheduledFor time.Time `json:"scheduled_for" binding:"required" example:"2023-09-20T09:00:00Z"`
+}
+
+// RevisionsResponse represents the response for listing a post's revisions with pagination
+type RevisionsResponse struct {
+	Revisions  []PostRevision     `json:"revisions"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+// RevisionDiffResponse represents a unified diff between two revisions of a post
+type RevisionDiffResponse struct {
+	PostID uint   `json:"post_id" example:"1"`
+	From   int    `json:"from" example:"1"`
+	To     int    `json:"to" example:"3"`
+	Diff   string `json:"diff" example:"--- revision 1\n+++ revision 3\n@@ -1 +1 @@\n-title: Old\n+title: New\n"`
 }
 
 // PostWithRelated represents a post with related posts
@@ -106,6 +506,35 @@ type ErrorResponse struct {
 	Error string `json:"error" example:"Invalid input"`
 }
 
+// OutboxEventsResponse represents the response for listing outbox events with pagination
+type OutboxEventsResponse struct {
+	Events     []OutboxEvent      `json:"events"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+// ReplayOutboxResponse reports how many dead-lettered events were queued again
+type ReplayOutboxResponse struct {
+	Replayed int64 `json:"replayed" example:"3"`
+}
+
+// MediaListResponse is the response for listing uploaded media
+type MediaListResponse struct {
+	Media      []Media            `json:"media"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+// AttachMediaRequest represents the request body for attaching media to a post
+type AttachMediaRequest struct {
+	MediaID uint   `json:"media_id" binding:"required" example:"1"`
+	Role    string `json:"role" binding:"omitempty,oneof=cover inline" example:"inline"` // Defaults to inline; attaching a new cover replaces the old one
+}
+
+// SlugRedirectResponse is returned with a 301 when a post is requested by a former slug
+type SlugRedirectResponse struct {
+	Slug     string `json:"slug" example:"my-first-blog-post"`
+	Location string `json:"location" example:"/api/v1/posts/by-slug/my-first-blog-post"`
+}
+
 // SuccessResponse represents a generic success response
 type SuccessResponse struct {
 	Message string `json:"message" example:"Operation completed successfully"`
@@ -0,0 +1,95 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/tracing"
+	"gorm.io/gorm"
+)
+
+// IndexPost records that a post's search document needs to be written. tx
+// must be the transaction that changes the post, so the event is committed
+// exactly when the change is.
+func IndexPost(tx *gorm.DB, postID uint) error {
+	return enqueue(tx, models.OutboxIndexPost, postID)
+}
+
+// DeletePost records that a post's search document needs to be removed
+func DeletePost(tx *gorm.DB, postID uint) error {
+	return enqueue(tx, models.OutboxDeletePost, postID)
+}
+
+// IndexPosts records index events for several posts in one insert
+func IndexPosts(tx *gorm.DB, postIDs []uint) error {
+	return enqueueAll(tx, models.OutboxIndexPost, postIDs)
+}
+
+// DeletePosts records delete events for several posts in one insert
+func DeletePosts(tx *gorm.DB, postIDs []uint) error {
+	return enqueueAll(tx, models.OutboxDeletePost, postIDs)
+}
+
+// enqueue records an event carrying the trace of the transaction's context,
+// so the relay's delivery continues it
+func enqueue(tx *gorm.DB, kind string, postID uint) error {
+	return tx.Create(&models.OutboxEvent{
+		Kind:          kind,
+		PostID:        postID,
+		Status:        models.OutboxPending,
+		NextAttemptAt: time.Now(),
+		TraceParent:   tracing.TraceParent(tx.Statement.Context),
+	}).Error
+}
+
+func enqueueAll(tx *gorm.DB, kind string, postIDs []uint) error {
+	if len(postIDs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	traceParent := tracing.TraceParent(tx.Statement.Context)
+	events := make([]models.OutboxEvent, len(postIDs))
+	for i, id := range postIDs {
+		events[i] = models.OutboxEvent{Kind: kind, PostID: id, Status: models.OutboxPending, NextAttemptAt: now, TraceParent: traceParent}
+	}
+	return tx.Create(&events).Error
+}
+
+// Backoff is how long to wait before retrying an event that has failed
+// attempts times: base doubled for every failure after the first, capped at max
+func Backoff(attempts int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
+
+// Replay queues a dead-lettered event for delivery again with a fresh set of
+// attempts. It reports false if the event doesn't exist or isn't dead.
+func Replay(db *gorm.DB, id uint) (bool, error) {
+	res := db.Model(&models.OutboxEvent{}).
+		Where("id = ? AND status = ?", id, models.OutboxDead).
+		Updates(replayed())
+	return res.RowsAffected > 0, res.Error
+}
+
+// ReplayAll queues every dead-lettered event again and returns how many there were
+func ReplayAll(db *gorm.DB) (int64, error) {
+	res := db.Model(&models.OutboxEvent{}).
+		Where("status = ?", models.OutboxDead).
+		Updates(replayed())
+	return res.RowsAffected, res.Error
+}
+
+func replayed() map[string]interface{} {
+	return map[string]interface{}{
+		"status":          models.OutboxPending,
+		"attempts":        0,
+		"next_attempt_at": time.Now(),
+	}
+}
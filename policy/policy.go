@@ -0,0 +1,124 @@
+package policy
+
+import "github.com/susbuntu/blog-api/models"
+
+// Action is something a principal may or may not be allowed to do
+type Action string
+
+const (
+	CreatePost       Action = "posts:create"
+	EditPost         Action = "posts:edit"
+	DeletePost       Action = "posts:delete"
+	RestorePost      Action = "posts:restore"
+	PurgePost        Action = "posts:purge"
+	ListAllTrash     Action = "posts:list_all_trash"
+	PublishPost      Action = "posts:publish"
+	ReadRevisions    Action = "posts:read_revisions"
+	ReadUnpublished  Action = "posts:read_unpublished"
+	ReadActivityLogs Action = "activity_logs:read"
+	ManageUsers      Action = "users:manage"
+	ManageAPIKeys    Action = "api_keys:manage"
+	ManageAllAPIKeys Action = "api_keys:manage_all"
+	CreateComment    Action = "comments:create"
+	EditComment      Action = "comments:edit"
+	DeleteComment    Action = "comments:delete"
+	ModerateComments Action = "comments:moderate"
+	UploadMedia      Action = "media:upload"
+	AttachMedia      Action = "media:attach"
+	DeleteMedia      Action = "media:delete"
+	ListAllMedia     Action = "media:list_all"
+	ManageSearch     Action = "search:manage"
+)
+
+// API key scopes
+const (
+	ScopePostsRead  = "posts:read"
+	ScopePostsWrite = "posts:write"
+	ScopeLogsRead   = "logs:read"
+)
+
+// actionScopes maps actions to the scope an API key needs to perform them.
+// Actions missing from the map can't be performed with an API key at all.
+var actionScopes = map[Action]string{
+	CreatePost:       ScopePostsWrite,
+	EditPost:         ScopePostsWrite,
+	DeletePost:       ScopePostsWrite,
+	RestorePost:      ScopePostsWrite,
+	PublishPost:      ScopePostsWrite,
+	ReadRevisions:    ScopePostsRead,
+	ReadUnpublished:  ScopePostsRead,
+	ReadActivityLogs: ScopeLogsRead,
+	CreateComment:    ScopePostsWrite,
+	EditComment:      ScopePostsWrite,
+	DeleteComment:    ScopePostsWrite,
+	UploadMedia:      ScopePostsWrite,
+	AttachMedia:      ScopePostsWrite,
+	DeleteMedia:      ScopePostsWrite,
+}
+
+// Resource is an owned object that an action targets, such as a post or comment
+type Resource interface {
+	OwnedBy(userID uint) bool
+}
+
+// Subject is the authenticated caller a decision is made for
+type Subject struct {
+	UserID uint
+	Role   string
+
+	// ViaAPIKey is true when the caller used an API key limited to Scopes
+	ViaAPIKey bool
+	Scopes    []string
+}
+
+// Can reports whether the subject may perform the action. resource is the
+// object being acted on and may be nil for actions that don't target one.
+// API keys act on behalf of their owner, so they need both the matching scope
+// and an owner role that allows the action.
+func Can(sub Subject, action Action, resource Resource) bool {
+	if sub.ViaAPIKey {
+		scope, ok := actionScopes[action]
+		if !ok || !HasScope(sub.Scopes, scope) {
+			return false
+		}
+	}
+
+	switch sub.Role {
+	case models.RoleAdmin:
+		return true
+	case models.RoleEditor:
+		switch action {
+		case CreatePost, EditPost, PublishPost, ReadUnpublished, ReadRevisions, ManageAPIKeys, CreateComment, DeleteComment, ModerateComments, UploadMedia, AttachMedia:
+			return true
+		case DeletePost, RestorePost, EditComment, DeleteMedia:
+			return owns(sub, resource)
+		}
+	case models.RoleAuthor:
+		switch action {
+		case CreatePost, ManageAPIKeys, CreateComment, UploadMedia:
+			return true
+		case EditPost, DeletePost, RestorePost, PublishPost, ReadUnpublished, ReadRevisions, EditComment, DeleteComment, AttachMedia, DeleteMedia:
+			return owns(sub, resource)
+		}
+	}
+	return false
+}
+
+// TrustedCommenter reports whether the subject's comments skip the moderation queue
+func TrustedCommenter(sub Subject) bool {
+	return sub.Role == models.RoleAdmin || sub.Role == models.RoleEditor
+}
+
+// HasScope reports whether scope is in the granted list
+func HasScope(granted []string, scope string) bool {
+	for _, s := range granted {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func owns(sub Subject, resource Resource) bool {
+	return resource != nil && resource.OwnedBy(sub.UserID)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/susbuntu/blog-api/config"
+	"github.com/susbuntu/blog-api/database"
+	"github.com/susbuntu/blog-api/search"
+)
+
+// runReindex handles the "reindex" command. It runs the same zero-downtime
+// reindex as the admin endpoint, so it is safe against a live cluster.
+func runReindex(cfg *config.Config, args []string) error {
+	if len(args) > 0 {
+		return errors.New("reindex takes no arguments")
+	}
+
+	db, err := openMigratedDB(cfg)
+	if err != nil {
+		return err
+	}
+	es := database.InitElasticsearch(cfg)
+
+	result, err := search.Reindex(context.Background(), es, db)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Alias %s now points at %s (%d copied, %d caught up, %d pruned)\n",
+		search.PostsIndex, result.Index, result.Copied, result.CaughtUp, result.Pruned)
+	return nil
+}
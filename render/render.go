@@ -0,0 +1,73 @@
+package render
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// Content formats a post body can be written in
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+	FormatPlain    = "plain"
+)
+
+// markdown is a CommonMark renderer with the GitHub extensions (tables,
+// strikethrough, autolinks and task lists). It omits raw HTML embedded in
+// the source; use the html format for posts written in HTML.
+var markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// sanitizer allowlists the elements and attributes user-generated content may
+// use. Scripts, event handlers, styles and javascript: URLs are stripped, and
+// links get rel="nofollow".
+var sanitizer = newSanitizer()
+
+func newSanitizer() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	// Keep the language class on fenced code blocks for client-side highlighting
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	return p
+}
+
+// HTML renders post content written in the given format as sanitized HTML.
+// Unknown formats are treated as plain text.
+func HTML(content, format string) string {
+	var out string
+	switch format {
+	case FormatMarkdown:
+		var buf bytes.Buffer
+		if err := markdown.Convert([]byte(content), &buf); err != nil {
+			out = plain(content)
+		} else {
+			out = buf.String()
+		}
+	case FormatHTML:
+		out = content
+	default:
+		out = plain(content)
+	}
+	return sanitizer.Sanitize(out)
+}
+
+// plain escapes text and turns blank-line separated blocks into paragraphs
+func plain(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	var b strings.Builder
+	for _, block := range strings.Split(content, "\n\n") {
+		block = strings.TrimSpace(block)
+		if block == "" {
+			continue
+		}
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(block), "\n", "<br>\n"))
+		b.WriteString("</p>\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,369 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/susbuntu/blog-api/metrics"
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/slug"
+	"gorm.io/gorm"
+)
+
+// Indexer receives the post changes MemoryPosts makes. search.SearchIndex
+// satisfies it.
+type Indexer interface {
+	IndexPost(ctx context.Context, post models.Post) error
+	DeletePost(ctx context.Context, postID uint) error
+}
+
+// MemoryPosts is a PostRepository held in process memory, for tests and
+// single-instance local runs. Search documents are updated directly after
+// each change rather than through the outbox, and revisions aren't kept.
+type MemoryPosts struct {
+	mu       sync.RWMutex
+	posts    map[uint]*models.Post // Trashed posts included, so their slugs stay taken
+	oldSlugs map[string]uint
+	lastID   uint
+	logs     *MemoryActivityLogs
+	index    Indexer
+}
+
+// NewMemoryPosts returns an empty repository that logs to logs and keeps
+// index up to date. index may be nil.
+func NewMemoryPosts(logs *MemoryActivityLogs, index Indexer) *MemoryPosts {
+	r := &MemoryPosts{
+		posts:    make(map[uint]*models.Post),
+		oldSlugs: make(map[string]uint),
+		logs:     logs,
+		index:    index,
+	}
+	logs.posts = r
+	return r
+}
+
+func (r *MemoryPosts) Create(ctx context.Context, post *models.Post) error {
+	r.mu.Lock()
+	postSlug, err := r.uniqueSlug(post.Title, 0)
+	if err != nil {
+		r.mu.Unlock()
+		return err
+	}
+
+	now := time.Now()
+	r.lastID++
+	post.ID = r.lastID
+	post.Slug = postSlug
+	post.CreatedAt = now
+	post.UpdatedAt = now
+	if post.Status == "" {
+		post.Status = models.PostDraft
+	}
+	r.posts[post.ID] = clonePost(post)
+	r.mu.Unlock()
+
+	r.logs.record("new_post", post.ID)
+	r.indexPost(ctx, *post)
+	return nil
+}
+
+func (r *MemoryPosts) Find(ctx context.Context, id uint) (models.Post, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	post, ok := r.posts[id]
+	if !ok || post.DeletedAt.Valid {
+		return models.Post{}, ErrNotFound
+	}
+	return *clonePost(post), nil
+}
+
+func (r *MemoryPosts) FindIDBySlug(ctx context.Context, postSlug string) (uint, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for id, post := range r.posts {
+		if post.Slug == postSlug && !post.DeletedAt.Valid {
+			return id, nil
+		}
+	}
+	if id, ok := r.oldSlugs[postSlug]; ok {
+		return id, nil
+	}
+	return 0, ErrNotFound
+}
+
+func (r *MemoryPosts) List(ctx context.Context, filter PostFilter, offset, limit int) ([]models.Post, error) {
+	matches := r.matching(filter)
+	sort.Slice(matches, func(i, j int) bool {
+		if !matches[i].CreatedAt.Equal(matches[j].CreatedAt) {
+			return matches[i].CreatedAt.After(matches[j].CreatedAt)
+		}
+		return matches[i].ID > matches[j].ID
+	})
+
+	if offset >= len(matches) {
+		return []models.Post{}, nil
+	}
+	matches = matches[offset:]
+	if limit > 0 && limit < len(matches) {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+func (r *MemoryPosts) Count(ctx context.Context, filter PostFilter) (int64, error) {
+	return int64(len(r.matching(filter))), nil
+}
+
+func (r *MemoryPosts) Update(ctx context.Context, id uint, edit PostEdit) (models.Post, error) {
+	r.mu.Lock()
+	stored, ok := r.posts[id]
+	if !ok || stored.DeletedAt.Valid {
+		r.mu.Unlock()
+		return models.Post{}, ErrNotFound
+	}
+
+	post := clonePost(stored)
+	if edit.Check != nil {
+		if err := edit.Check(post); err != nil {
+			r.mu.Unlock()
+			return models.Post{}, err
+		}
+	}
+
+	oldTitle := post.Title
+	edit.Apply(post)
+
+	// Keep the old slug working as a redirect
+	if post.Title != oldTitle {
+		next, err := r.uniqueSlug(post.Title, post.ID)
+		if err != nil {
+			r.mu.Unlock()
+			return models.Post{}, err
+		}
+		if next != post.Slug {
+			delete(r.oldSlugs, next)
+			r.oldSlugs[post.Slug] = post.ID
+			post.Slug = next
+		}
+	}
+
+	post.UpdatedAt = time.Now()
+	r.posts[id] = clonePost(post)
+	r.mu.Unlock()
+
+	if edit.LogAction != "" {
+		r.logs.record(edit.LogAction, post.ID)
+	}
+	r.indexPost(ctx, *post)
+	return *post, nil
+}
+
+func (r *MemoryPosts) Delete(ctx context.Context, id uint, check func(*models.Post) error) (models.Post, error) {
+	r.mu.Lock()
+	stored, ok := r.posts[id]
+	if !ok || stored.DeletedAt.Valid {
+		r.mu.Unlock()
+		return models.Post{}, ErrNotFound
+	}
+
+	post := clonePost(stored)
+	if err := check(post); err != nil {
+		r.mu.Unlock()
+		return models.Post{}, err
+	}
+
+	stored.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.mu.Unlock()
+
+	r.logs.record("delete_post", post.ID)
+	if r.index != nil {
+		if err := r.index.DeletePost(ctx, post.ID); err != nil {
+			metrics.SearchSyncFailures.WithLabelValues("delete").Inc()
+			log.Printf("Failed to remove post %d from the search index: %v", post.ID, err)
+		}
+	}
+	return *post, nil
+}
+
+// matching returns copies of the live posts matching filter
+func (r *MemoryPosts) matching(filter PostFilter) []models.Post {
+	var ids map[uint]bool
+	if filter.IDs != nil {
+		ids = make(map[uint]bool, len(filter.IDs))
+		for _, id := range filter.IDs {
+			ids[id] = true
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matches := []models.Post{}
+	for _, post := range r.posts {
+		if post.DeletedAt.Valid || !filter.Visibility.Allows(post) {
+			continue
+		}
+		if filter.Tag != "" && !hasTag(post, filter.Tag) {
+			continue
+		}
+		if ids != nil && !ids[post.ID] {
+			continue
+		}
+		matches = append(matches, *clonePost(post))
+	}
+	return matches
+}
+
+// uniqueSlug mirrors slug.ForPost: a slug is taken while any other post uses
+// it, trashed posts and old redirect slugs included. Callers hold the lock.
+func (r *MemoryPosts) uniqueSlug(title string, postID uint) (string, error) {
+	return slug.Unique(slug.Make(title), func(candidate string) (bool, error) {
+		for id, post := range r.posts {
+			if id != postID && post.Slug == candidate {
+				return true, nil
+			}
+		}
+		owner, ok := r.oldSlugs[candidate]
+		return ok && owner != postID, nil
+	})
+}
+
+func (r *MemoryPosts) indexPost(ctx context.Context, post models.Post) {
+	if r.index == nil {
+		return
+	}
+	if err := r.index.IndexPost(ctx, post); err != nil {
+		metrics.SearchSyncFailures.WithLabelValues("index").Inc()
+		log.Printf("Failed to index post %d: %v", post.ID, err)
+	}
+}
+
+// clonePost copies a post so callers can't change the stored one through shared slices
+func clonePost(post *models.Post) *models.Post {
+	clone := *post
+	clone.Tags = append(models.StringArray(nil), post.Tags...)
+	clone.Media = append([]models.PostMedia(nil), post.Media...)
+	return &clone
+}
+
+func hasTag(post *models.Post, tag string) bool {
+	for _, t := range post.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// MemoryActivityLogs is an ActivityLogRepository held in process memory. It
+// is written by the MemoryPosts it is passed to.
+type MemoryActivityLogs struct {
+	mu     sync.RWMutex
+	logs   []models.ActivityLog
+	lastID uint
+	posts  *MemoryPosts
+}
+
+func NewMemoryActivityLogs() *MemoryActivityLogs {
+	return &MemoryActivityLogs{}
+}
+
+func (r *MemoryActivityLogs) List(ctx context.Context, offset, limit int) ([]models.ActivityLog, error) {
+	r.mu.RLock()
+	page := []models.ActivityLog{}
+	for i := len(r.logs) - 1 - offset; i >= 0 && len(page) < limit; i-- {
+		page = append(page, r.logs[i])
+	}
+	r.mu.RUnlock()
+
+	// Attach each entry's post as it is now, trashed or not
+	if r.posts != nil {
+		r.posts.mu.RLock()
+		for i := range page {
+			if page[i].PostID == nil {
+				continue
+			}
+			if post, ok := r.posts.posts[*page[i].PostID]; ok {
+				page[i].Post = *clonePost(post)
+			}
+		}
+		r.posts.mu.RUnlock()
+	}
+	return page, nil
+}
+
+func (r *MemoryActivityLogs) Count(ctx context.Context) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return int64(len(r.logs)), nil
+}
+
+func (r *MemoryActivityLogs) record(action string, postID uint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	r.logs = append(r.logs, models.ActivityLog{ID: r.lastID, Action: action, PostID: &postID, LoggedAt: time.Now()})
+}
+
+// MemoryUsers is a UserRepository held in process memory
+type MemoryUsers struct {
+	mu     sync.RWMutex
+	users  map[uint]models.User
+	lastID uint
+}
+
+func NewMemoryUsers() *MemoryUsers {
+	return &MemoryUsers{users: make(map[uint]models.User)}
+}
+
+func (r *MemoryUsers) Create(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.users {
+		if existing.Email == user.Email {
+			return ErrDuplicate
+		}
+	}
+
+	now := time.Now()
+	r.lastID++
+	user.ID = r.lastID
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	if user.Role == "" {
+		user.Role = models.RoleAuthor
+	}
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *MemoryUsers) Find(ctx context.Context, id uint) (models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return models.User{}, ErrNotFound
+	}
+	return user, nil
+}
+
+func (r *MemoryUsers) FindByEmail(ctx context.Context, email string) (models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return models.User{}, ErrNotFound
+}
@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/outbox"
+	"github.com/susbuntu/blog-api/slug"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresPosts is the PostRepository backed by Postgres. Search index changes
+// go through the outbox in the same transaction as the post change, and every
+// create and update is recorded as a revision.
+type PostgresPosts struct {
+	DB *gorm.DB
+}
+
+func NewPostgresPosts(db *gorm.DB) *PostgresPosts {
+	return &PostgresPosts{DB: db}
+}
+
+// slugAttempts bounds how many times a post write that lost a race for its
+// slug is tried
+const slugAttempts = 3
+
+func (r *PostgresPosts) Create(ctx context.Context, post *models.Post) error {
+	return retrySlugConflicts(func() error {
+		return r.create(ctx, post)
+	})
+}
+
+func (r *PostgresPosts) create(ctx context.Context, post *models.Post) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		postSlug, err := slug.ForPost(tx, post.Title, 0)
+		if err != nil {
+			return err
+		}
+		post.Slug = postSlug
+
+		if err := tx.Create(post).Error; err != nil {
+			return err
+		}
+
+		// Record the first revision
+		if _, err := RecordRevision(tx, *post, post.AuthorID, nil); err != nil {
+			return err
+		}
+
+		if err := tx.Create(&models.ActivityLog{Action: "new_post", PostID: &post.ID}).Error; err != nil {
+			return err
+		}
+
+		// Queue indexing in Elasticsearch; the outbox relay delivers it once committed
+		return outbox.IndexPost(tx, post.ID)
+	})
+}
+
+func (r *PostgresPosts) Find(ctx context.Context, id uint) (models.Post, error) {
+	var post models.Post
+	err := WithMedia(r.DB.WithContext(ctx)).First(&post, id).Error
+	return post, notFound(err)
+}
+
+func (r *PostgresPosts) FindIDBySlug(ctx context.Context, postSlug string) (uint, error) {
+	db := r.DB.WithContext(ctx)
+
+	var id uint
+	err := db.Model(&models.Post{}).Where("slug = ?", postSlug).Pluck("id", &id).Error
+	if err == nil && id == 0 {
+		err = db.Model(&models.PostSlug{}).Where("slug = ?", postSlug).Pluck("post_id", &id).Error
+	}
+	if err != nil {
+		return 0, err
+	}
+	if id == 0 {
+		return 0, ErrNotFound
+	}
+	return id, nil
+}
+
+func (r *PostgresPosts) List(ctx context.Context, filter PostFilter, offset, limit int) ([]models.Post, error) {
+	query := filtered(WithMedia(r.DB.WithContext(ctx)), filter).Order("created_at DESC").Offset(offset)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	posts := []models.Post{}
+	err := query.Find(&posts).Error
+	return posts, err
+}
+
+func (r *PostgresPosts) Count(ctx context.Context, filter PostFilter) (int64, error) {
+	var total int64
+	err := filtered(r.DB.WithContext(ctx).Model(&models.Post{}), filter).Count(&total).Error
+	return total, err
+}
+
+func (r *PostgresPosts) Update(ctx context.Context, id uint, edit PostEdit) (models.Post, error) {
+	var post models.Post
+	err := retrySlugConflicts(func() error {
+		var err error
+		post, err = r.update(ctx, id, edit)
+		return err
+	})
+	return post, err
+}
+
+func (r *PostgresPosts) update(ctx context.Context, id uint, edit PostEdit) (models.Post, error) {
+	var post models.Post
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the post so concurrent edits get sequential revisions
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&post, id).Error; err != nil {
+			return notFound(err)
+		}
+		if edit.Check != nil {
+			if err := edit.Check(&post); err != nil {
+				return err
+			}
+		}
+
+		// Posts created before revision history existed get their current state recorded first
+		if edit.Revise {
+			if err := EnsureBaselineRevision(tx, post); err != nil {
+				return err
+			}
+		}
+
+		oldTitle := post.Title
+		edit.Apply(&post)
+
+		// Keep the old slug working as a redirect
+		if post.Title != oldTitle {
+			if err := slug.Rename(tx, &post); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Save(&post).Error; err != nil {
+			return err
+		}
+
+		if edit.Revise {
+			if _, err := RecordRevision(tx, post, edit.EditorID, edit.RestoredFrom); err != nil {
+				return err
+			}
+		}
+		if edit.LogAction != "" {
+			if err := tx.Create(&models.ActivityLog{Action: edit.LogAction, PostID: &post.ID}).Error; err != nil {
+				return err
+			}
+		}
+
+		// Queue the Elasticsearch update
+		return outbox.IndexPost(tx, post.ID)
+	})
+	return post, err
+}
+
+func (r *PostgresPosts) Delete(ctx context.Context, id uint, check func(*models.Post) error) (models.Post, error) {
+	var post models.Post
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&post, id).Error; err != nil {
+			return notFound(err)
+		}
+		if err := check(&post); err != nil {
+			return err
+		}
+
+		// Move the post to the trash. Comments, revisions and activity logs are kept
+		// so a restore brings the post back intact.
+		if err := tx.Delete(&post).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(&models.ActivityLog{Action: "delete_post", PostID: &post.ID}).Error; err != nil {
+			return err
+		}
+
+		// Queue removal from Elasticsearch
+		return outbox.DeletePost(tx, post.ID)
+	})
+	return post, err
+}
+
+// retrySlugConflicts runs a post write again when it fails on a unique
+// violation. Slugs are the only unique post columns, and they are checked
+// before they are stored, so a violation means a concurrent write took the
+// same slug in between. The write is a whole transaction, so the next attempt
+// sees the other post and picks the next free slug.
+func retrySlugConflicts(write func() error) error {
+	var err error
+	for attempt := 0; attempt < slugAttempts; attempt++ {
+		if err = write(); !errors.Is(err, gorm.ErrDuplicatedKey) {
+			return err
+		}
+	}
+	return err
+}
+
+// WithMedia preloads a post query's attachments, cover first
+func WithMedia(db *gorm.DB) *gorm.DB {
+	return db.Preload("Media", func(db *gorm.DB) *gorm.DB {
+		return db.Order("role ASC, created_at ASC")
+	}).Preload("Media.Media")
+}
+
+// Visible restricts a post query to the posts visible to a reader
+func Visible(db *gorm.DB, visibility models.PostVisibility) *gorm.DB {
+	switch {
+	case visibility.All:
+		return db
+	case visibility.AuthorID != nil:
+		return db.Where("status = ? OR author_id = ?", models.PostPublished, *visibility.AuthorID)
+	default:
+		return db.Where("status = ?", models.PostPublished)
+	}
+}
+
+// filtered applies a PostFilter to a post query. Tags are matched with the
+// GIN index on tags.
+func filtered(db *gorm.DB, filter PostFilter) *gorm.DB {
+	db = Visible(db, filter.Visibility)
+	if filter.Tag != "" {
+		db = db.Where("tags @> ARRAY[?]", filter.Tag)
+	}
+	if filter.IDs != nil {
+		db = db.Where("id IN ?", filter.IDs)
+	}
+	return db
+}
+
+// PostgresActivityLogs is the ActivityLogRepository backed by Postgres
+type PostgresActivityLogs struct {
+	DB *gorm.DB
+}
+
+func NewPostgresActivityLogs(db *gorm.DB) *PostgresActivityLogs {
+	return &PostgresActivityLogs{DB: db}
+}
+
+func (r *PostgresActivityLogs) List(ctx context.Context, offset, limit int) ([]models.ActivityLog, error) {
+	var logs []models.ActivityLog
+	err := r.DB.WithContext(ctx).
+		Preload("Post", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order("logged_at DESC").Offset(offset).Limit(limit).
+		Find(&logs).Error
+	return logs, err
+}
+
+func (r *PostgresActivityLogs) Count(ctx context.Context) (int64, error) {
+	var total int64
+	err := r.DB.WithContext(ctx).Model(&models.ActivityLog{}).Count(&total).Error
+	return total, err
+}
+
+// PostgresUsers is the UserRepository backed by Postgres
+type PostgresUsers struct {
+	DB *gorm.DB
+}
+
+func NewPostgresUsers(db *gorm.DB) *PostgresUsers {
+	return &PostgresUsers{DB: db}
+}
+
+func (r *PostgresUsers) Create(ctx context.Context, user *models.User) error {
+	err := r.DB.WithContext(ctx).Create(user).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *PostgresUsers) Find(ctx context.Context, id uint) (models.User, error) {
+	var user models.User
+	err := r.DB.WithContext(ctx).First(&user, id).Error
+	return user, notFound(err)
+}
+
+func (r *PostgresUsers) FindByEmail(ctx context.Context, email string) (models.User, error) {
+	var user models.User
+	err := r.DB.WithContext(ctx).Where("email = ?", email).First(&user).Error
+	return user, notFound(err)
+}
+
+// notFound translates GORM's missing record error into ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
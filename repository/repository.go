@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/susbuntu/blog-api/models"
+)
+
+var (
+	// ErrNotFound is returned when the requested record doesn't exist
+	ErrNotFound = errors.New("record not found")
+	// ErrDuplicate is returned when a unique field such as an email is already taken
+	ErrDuplicate = errors.New("duplicate record")
+)
+
+// PostRepository stores posts. Writes record their activity log entry and get
+// the post's search document updated along with the change, and reads skip
+// trashed posts.
+type PostRepository interface {
+	// Create gives the post a unique slug, stores it and logs "new_post"
+	Create(ctx context.Context, post *models.Post) error
+	// Find loads a post with its attached media
+	Find(ctx context.Context, id uint) (models.Post, error)
+	// FindIDBySlug resolves a post's current or former slug to its ID
+	FindIDBySlug(ctx context.Context, slug string) (uint, error)
+	// List returns the posts matching filter with their media, newest first.
+	// A limit of 0 returns every match.
+	List(ctx context.Context, filter PostFilter, offset, limit int) ([]models.Post, error)
+	// Count counts the posts matching filter
+	Count(ctx context.Context, filter PostFilter) (int64, error)
+	// Update applies an edit to a post and returns the result
+	Update(ctx context.Context, id uint, edit PostEdit) (models.Post, error)
+	// Delete moves a post to the trash and logs "delete_post". check runs on
+	// the post first; an error from it cancels the delete and is returned as is.
+	Delete(ctx context.Context, id uint, check func(*models.Post) error) (models.Post, error)
+}
+
+// PostFilter narrows a post listing
+type PostFilter struct {
+	Visibility models.PostVisibility
+	// Tag, when set, only matches posts with this tag
+	Tag string
+	// IDs, when set, only matches these posts
+	IDs []uint
+}
+
+// PostEdit is a change made by PostRepository.Update. The post is locked from
+// Check until the change is stored, so concurrent edits apply one at a time.
+// A changed title moves the post to a new slug, keeping the old one as a
+// redirect.
+type PostEdit struct {
+	// Check runs on the current post; an error from it cancels the edit and is returned as is
+	Check func(*models.Post) error
+	// Apply makes the change
+	Apply func(*models.Post)
+	// Revise records the edited post as a new revision by EditorID
+	Revise   bool
+	EditorID *uint
+	// RestoredFrom, when set, marks the new revision as a restore of that
+	// earlier revision
+	RestoredFrom *int
+	// LogAction, when set, is written to the activity log
+	LogAction string
+}
+
+// ActivityLogRepository reads the activity log. Entries are written by the
+// repositories whose changes they record.
+type ActivityLogRepository interface {
+	// List returns entries with their posts, trashed ones included, newest
+	// first. Entries about purged posts keep their PostID with no post attached.
+	List(ctx context.Context, offset, limit int) ([]models.ActivityLog, error)
+	Count(ctx context.Context) (int64, error)
+}
+
+// UserRepository stores accounts
+type UserRepository interface {
+	// Create stores a new user, returning ErrDuplicate if the email is taken
+	Create(ctx context.Context, user *models.User) error
+	Find(ctx context.Context, id uint) (models.User, error)
+	FindByEmail(ctx context.Context, email string) (models.User, error)
+}
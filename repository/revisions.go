@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"github.com/susbuntu/blog-api/models"
+	"gorm.io/gorm"
+)
+
+// RecordRevision snapshots the post as its next revision. Callers must hold a
+// lock on the post row so revision numbers stay sequential.
+func RecordRevision(tx *gorm.DB, post models.Post, editorID *uint, restoredFrom *int) (models.PostRevision, error) {
+	var latest int
+	if err := tx.Model(&models.PostRevision{}).Where("post_id = ?", post.ID).Select("COALESCE(MAX(revision), 0)").Scan(&latest).Error; err != nil {
+		return models.PostRevision{}, err
+	}
+
+	revision := models.PostRevision{
+		PostID:        post.ID,
+		Revision:      latest + 1,
+		Title:         post.Title,
+		Content:       post.Content,
+		ContentFormat: post.ContentFormat,
+		Tags:          post.Tags,
+		EditorID:      editorID,
+		RestoredFrom:  restoredFrom,
+	}
+
+	return revision, tx.Create(&revision).Error
+}
+
+// EnsureBaselineRevision records the current state of a post that predates
+// revision history, so its first update can still be diffed and undone
+func EnsureBaselineRevision(tx *gorm.DB, post models.Post) error {
+	var count int64
+	if err := tx.Model(&models.PostRevision{}).Where("post_id = ?", post.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	return tx.Create(&models.PostRevision{
+		PostID:        post.ID,
+		Revision:      1,
+		Title:         post.Title,
+		Content:       post.Content,
+		ContentFormat: post.ContentFormat,
+		Tags:          post.Tags,
+		EditorID:      post.AuthorID,
+	}).Error
+}
@@ -2,48 +2,165 @@ package routes
 
 import (
 	"github.com/gin-gonic/gin"
-	"github.com/go-redis/redis/v8"
-	"github.com/olivere/elastic/v7"
+	"github.com/susbuntu/blog-api/auth"
+	"github.com/susbuntu/blog-api/config"
 	"github.com/susbuntu/blog-api/handlers"
-	"gorm.io/gorm"
+	"github.com/susbuntu/blog-api/metrics"
+	"github.com/susbuntu/blog-api/middleware"
+	"github.com/susbuntu/blog-api/tracing"
 )
 
-func SetupRoutes(router *gin.Engine, db *gorm.DB, redis *redis.Client, es *elastic.Client) {
+// SetupRoutes registers the API. Endpoints backed by tables only Postgres has,
+// such as comments, media and revisions, are left out on memory storage, and
+// the index administration endpoints also need Elasticsearch.
+func SetupRoutes(router *gin.Engine, cfg *config.Config, stores handlers.Stores) {
 	// Initialize handler
-	h := handlers.NewHandler(db, redis, es)
+	tokens := auth.NewTokenManager(cfg.JWT)
+	h := handlers.NewHandler(cfg, stores, tokens)
+	requireAuth := middleware.RequireAuth(tokens, stores.Users, stores.DB)
+	optionalAuth := middleware.OptionalAuth(tokens, stores.Users, stores.DB)
+	withDB := stores.DB != nil
+
+	// Trace, count and time every request
+	router.Use(tracing.Middleware(cfg.Tracing.ServiceName), middleware.Metrics())
 
 	// API routes group
 	api := router.Group("/api/v1")
 	{
+		// Auth routes
+		authRoutes := api.Group("/auth")
+		{
+			authRoutes.POST("/register", h.Register)
+			authRoutes.POST("/login", h.Login)
+			authRoutes.POST("/refresh", h.Refresh)
+			authRoutes.GET("/me", requireAuth, h.Me)
+		}
+
 		// Posts routes
 		posts := api.Group("/posts")
 		{
-			posts.POST("", h.CreatePost)
-			posts.GET("", h.GetAllPosts)
-			posts.GET("/:id", h.GetPost)
-			posts.GET("/:id/related", h.GetPostWithRelated)
-			posts.PUT("/:id", h.UpdatePost)
-			posts.DELETE("/:id", h.DeletePost)
-			posts.GET("/search-by-tag", h.SearchPostsByTag)
-			posts.GET("/search", h.SearchPosts)
+			posts.POST("", requireAuth, h.CreatePost)
+			posts.GET("", optionalAuth, h.GetAllPosts)
+			posts.GET("/:id", optionalAuth, h.GetPost)
+			posts.GET("/by-slug/:slug", optionalAuth, h.GetPostBySlug)
+			posts.GET("/:id/related", optionalAuth, h.GetPostWithRelated)
+			posts.PUT("/:id", requireAuth, h.UpdatePost)
+			posts.DELETE("/:id", requireAuth, h.DeletePost)
+			posts.GET("/search-by-tag", optionalAuth, h.SearchPostsByTag)
+			posts.GET("/search", optionalAuth, h.SearchPosts)
+
+			// Lifecycle routes
+			posts.POST("/:id/publish", requireAuth, h.PublishPost)
+			posts.POST("/:id/unpublish", requireAuth, h.UnpublishPost)
+			posts.POST("/:id/archive", requireAuth, h.ArchivePost)
 		}
 
 		// Activity logs routes
-		api.GET("/activity-logs", h.GetActivityLogs)
+		api.GET("/activity-logs", requireAuth, h.GetActivityLogs)
 	}
 
-	// Health check
-	// @Summary Health Check
-	// @Description Get the health status of the API
-	// @Tags health
-	// @Accept json
-	// @Produce json
-	// @Success 200 {object} map[string]string
-	// @Router /health [get]
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status": "OK",
-			"message": "Blog API is running",
-		})
-	})
+	// Everything below queries Postgres directly, so it isn't registered on
+	// memory storage: the feeds and sitemaps 404 there, at the site root as well
+	if withDB {
+		posts := api.Group("/posts")
+		{
+			// Scheduling routes
+			posts.POST("/:id/schedule", requireAuth, h.SchedulePost)
+			posts.DELETE("/:id/schedule", requireAuth, h.UnschedulePost)
+
+			// Trash routes
+			posts.GET("/trash", requireAuth, h.GetTrash)
+			posts.POST("/:id/restore", requireAuth, h.RestorePost)
+			posts.DELETE("/:id/purge", requireAuth, h.PurgePost)
+
+			// Revision routes
+			posts.GET("/:id/revisions", requireAuth, h.GetRevisions)
+			posts.GET("/:id/revisions/diff", requireAuth, h.DiffRevisions)
+			posts.GET("/:id/revisions/:revision", requireAuth, h.GetRevision)
+			posts.POST("/:id/revisions/:revision/restore", requireAuth, h.RestoreRevision)
+
+			// Media attachment routes
+			posts.GET("/:id/media", optionalAuth, h.GetPostMedia)
+			posts.POST("/:id/media", requireAuth, h.AttachMedia)
+			posts.DELETE("/:id/media/:media_id", requireAuth, h.DetachMedia)
+
+			// Comment routes
+			posts.POST("/:id/comments", requireAuth, h.CreateComment)
+			posts.GET("/:id/comments", optionalAuth, h.GetComments)
+			posts.PUT("/:id/comments/:comment_id", requireAuth, h.UpdateComment)
+			posts.DELETE("/:id/comments/:comment_id", requireAuth, h.DeleteComment)
+		}
+
+		// Comment moderation routes
+		moderation := api.Group("/comments/moderation", requireAuth)
+		{
+			moderation.GET("", h.GetModerationQueue)
+			moderation.POST("", h.ModerateComments)
+		}
+
+		// User management routes
+		users := api.Group("/users", requireAuth)
+		{
+			users.GET("", h.GetUsers)
+			users.PUT("/:id/role", h.UpdateUserRole)
+			users.DELETE("/:id", h.DeleteUser)
+		}
+
+		// Media routes
+		media := api.Group("/media")
+		{
+			media.POST("", requireAuth, h.UploadMedia)
+			media.GET("", requireAuth, h.GetMediaList)
+			media.GET("/:id", h.GetMedia)
+			media.GET("/:id/content", h.GetMediaContent)
+			media.GET("/:id/variants/:name", h.GetMediaVariant)
+			media.DELETE("/:id", requireAuth, h.DeleteMedia)
+		}
+
+		// API key routes
+		apiKeys := api.Group("/api-keys", requireAuth)
+		{
+			apiKeys.POST("", h.CreateAPIKey)
+			apiKeys.GET("", h.GetAPIKeys)
+			apiKeys.DELETE("/:id", h.RevokeAPIKey)
+		}
+
+		// Search administration routes
+		searchAdmin := api.Group("/admin/search", requireAuth)
+		{
+			searchAdmin.GET("/outbox", h.GetOutboxEvents)
+			searchAdmin.POST("/outbox/replay", h.ReplayOutboxEvents)
+			searchAdmin.POST("/outbox/:id/replay", h.ReplayOutboxEvent)
+
+			if stores.ES != nil {
+				searchAdmin.POST("/reindex", h.StartReindex)
+				searchAdmin.GET("/indices", h.GetSearchIndices)
+				searchAdmin.GET("/consistency", h.GetConsistencyCheck)
+				searchAdmin.POST("/consistency", h.StartConsistencyCheck)
+			}
+		}
+
+		// Feeds and sitemaps are served at the site root, where feed readers and
+		// crawlers look for them, as well as under /api/v1 with the rest of the API
+		for _, group := range []gin.IRoutes{router, api} {
+			// Feed routes
+			group.GET("/feed.rss", h.GetRSSFeed)
+			group.GET("/feed.atom", h.GetAtomFeed)
+			group.GET("/tags/:tag/feed.atom", h.GetTagAtomFeed)
+
+			// Sitemap routes
+			group.GET("/sitemap.xml", h.GetSitemap)
+			group.GET("/sitemaps/:name", h.GetSitemapPage)
+		}
+	}
+
+	// Prometheus metrics
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// Probes are served at the site root, where orchestrators look for them, as
+	// well as under /api/v1 with the rest of the API
+	for _, group := range []gin.IRoutes{router, api} {
+		group.GET("/livez", h.Livez)
+		group.GET("/readyz", h.Readyz)
+	}
 }
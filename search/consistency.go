@@ -0,0 +1,262 @@
+package search
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/susbuntu/blog-api/metrics"
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/outbox"
+	"gorm.io/gorm"
+)
+
+// consistencyLockID is the Postgres advisory lock held while a consistency
+// check runs, so only one replica checks at a time
+const consistencyLockID = 727311019
+
+// driftSampleSize caps how many IDs of each kind of drift a check records
+const driftSampleSize = 100
+
+// ErrConsistencyCheckRunning is returned when another check holds the lock
+var ErrConsistencyCheckRunning = errors.New("a consistency check is already running")
+
+// CheckConsistency compares the index with Postgres and waits for the result
+func CheckConsistency(ctx context.Context, es *elastic.Client, db *gorm.DB, repair bool) (*models.ConsistencyCheck, error) {
+	type outcome struct {
+		check *models.ConsistencyCheck
+		err   error
+	}
+	done := make(chan outcome, 1)
+
+	err := StartConsistencyCheck(ctx, es, db, repair, func(check *models.ConsistencyCheck, err error) {
+		done <- outcome{check, err}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	o := <-done
+	return o.check, o.err
+}
+
+// StartConsistencyCheck takes the consistency check lock and compares the
+// index with Postgres in the background, calling done with the outcome. It
+// returns ErrConsistencyCheckRunning straight away if another check is in
+// progress.
+//
+// Live posts are compared with their documents in batches by ID and content
+// hash, finding posts that are missing from the index or whose document is
+// stale. The index is then scrolled for orphaned documents, whose posts were
+// trashed or purged. With repair set, index and delete events for the drift
+// are queued on the outbox for the relay to deliver. The result is saved and
+// published as metrics.
+func StartConsistencyCheck(ctx context.Context, es *elastic.Client, db *gorm.DB, repair bool, done func(*models.ConsistencyCheck, error)) error {
+	var check *models.ConsistencyCheck
+	return startLocked(db, consistencyLockID, "consistency check", ErrConsistencyCheckRunning, func() error {
+		var err error
+		if check, err = checkConsistency(ctx, es, db, repair); err != nil {
+			return err
+		}
+		return db.WithContext(ctx).Create(check).Error
+	}, func(err error) {
+		if err != nil {
+			log.Printf("Consistency check failed: %v", err)
+			metrics.RecordConsistencyCheck(nil)
+			done(nil, err)
+			return
+		}
+		metrics.RecordConsistencyCheck(check)
+		done(check, nil)
+	})
+}
+
+func checkConsistency(ctx context.Context, es *elastic.Client, db *gorm.DB, repair bool) (*models.ConsistencyCheck, error) {
+	check := &models.ConsistencyCheck{
+		StartedAt:   time.Now(),
+		MissingIDs:  models.IDArray{},
+		StaleIDs:    models.IDArray{},
+		OrphanedIDs: models.IDArray{},
+		Repaired:    repair,
+	}
+
+	var posts []models.Post
+	err := db.WithContext(ctx).Order("id ASC").FindInBatches(&posts, reindexBatchSize, func(tx *gorm.DB, batch int) error {
+		missing, stale, err := compareBatch(ctx, es, db, posts)
+		if err != nil {
+			return err
+		}
+
+		check.Posts += len(posts)
+		check.Missing += len(missing)
+		check.Stale += len(stale)
+		check.MissingIDs = sample(check.MissingIDs, missing)
+		check.StaleIDs = sample(check.StaleIDs, stale)
+
+		if repair {
+			return outbox.IndexPosts(db.WithContext(ctx), append(missing, stale...))
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return nil, fmt.Errorf("compare posts: %w", err)
+	}
+
+	if err := findOrphans(ctx, es, db, check, repair); err != nil {
+		return nil, fmt.Errorf("find orphans: %w", err)
+	}
+
+	check.Duration = time.Since(check.StartedAt)
+	log.Printf("Consistency check completed: %d posts, %d documents, %d missing, %d stale, %d orphaned in %s",
+		check.Posts, check.Documents, check.Missing, check.Stale, check.Orphaned, check.Duration)
+	return check, nil
+}
+
+// compareBatch returns which of posts have no document and which have one that
+// doesn't match the post. Posts still being synced by the outbox are skipped.
+func compareBatch(ctx context.Context, es *elastic.Client, db *gorm.DB, posts []models.Post) (missing, stale []uint, err error) {
+	ids := make([]uint, len(posts))
+	mget := es.Mget()
+	for i, post := range posts {
+		ids[i] = post.ID
+		mget.Add(elastic.NewMultiGetItem().Index(PostsIndex).Id(strconv.FormatUint(uint64(post.ID), 10)))
+	}
+
+	syncing, err := syncingPostIDs(ctx, db, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res, err := mget.Do(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	docs := make(map[string]*elastic.GetResult, len(res.Docs))
+	for _, doc := range res.Docs {
+		if doc.Error != nil {
+			return nil, nil, fmt.Errorf("get document %s: %s", doc.Id, doc.Error.Reason)
+		}
+		docs[doc.Id] = doc
+	}
+
+	for _, post := range posts {
+		if syncing[post.ID] {
+			continue
+		}
+
+		doc := docs[strconv.FormatUint(uint64(post.ID), 10)]
+		if doc == nil || !doc.Found {
+			missing = append(missing, post.ID)
+			continue
+		}
+
+		var indexed models.PostSearchResult
+		if err := json.Unmarshal(doc.Source, &indexed); err != nil {
+			stale = append(stale, post.ID)
+			continue
+		}
+		if documentHash(indexed) != documentHash(NewPostDocument(post)) {
+			stale = append(stale, post.ID)
+		}
+	}
+	return missing, stale, nil
+}
+
+// findOrphans scrolls the index for documents without a live post
+func findOrphans(ctx context.Context, es *elastic.Client, db *gorm.DB, check *models.ConsistencyCheck, repair bool) error {
+	scroll := es.Scroll(PostsIndex).FetchSource(false).Size(reindexBatchSize)
+	defer scroll.Clear(context.Background())
+
+	for {
+		res, err := scroll.Do(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		ids := make([]uint, 0, len(res.Hits.Hits))
+		for _, hit := range res.Hits.Hits {
+			if id, err := strconv.ParseUint(hit.Id, 10, 64); err == nil {
+				ids = append(ids, uint(id))
+			}
+		}
+		check.Documents += len(res.Hits.Hits)
+
+		live, err := livePostIDs(ctx, db, ids)
+		if err != nil {
+			return err
+		}
+		syncing, err := syncingPostIDs(ctx, db, ids)
+		if err != nil {
+			return err
+		}
+
+		var orphaned []uint
+		for _, id := range ids {
+			if !live[id] && !syncing[id] {
+				orphaned = append(orphaned, id)
+			}
+		}
+		check.Orphaned += len(orphaned)
+		check.OrphanedIDs = sample(check.OrphanedIDs, orphaned)
+
+		if repair {
+			if err := outbox.DeletePosts(db.WithContext(ctx), orphaned); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// syncingPostIDs returns which of ids have outbox events still waiting for
+// delivery. Dead-lettered events don't count, since nothing will deliver them.
+func syncingPostIDs(ctx context.Context, db *gorm.DB, ids []uint) (map[uint]bool, error) {
+	var pending []uint
+	err := db.WithContext(ctx).Model(&models.OutboxEvent{}).
+		Where("status = ? AND post_id IN ?", models.OutboxPending, ids).
+		Distinct().Pluck("post_id", &pending).Error
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[uint]bool, len(pending))
+	for _, id := range pending {
+		found[id] = true
+	}
+	return found, nil
+}
+
+// documentHash fingerprints a search document. Times are compared in UTC and
+// empty tag lists like missing ones, since neither is a meaningful difference.
+func documentHash(doc models.PostSearchResult) [sha256.Size]byte {
+	if len(doc.Tags) == 0 {
+		doc.Tags = nil
+	}
+	if doc.PublishedAt != nil {
+		publishedAt := doc.PublishedAt.UTC()
+		doc.PublishedAt = &publishedAt
+	}
+
+	b, _ := json.Marshal(doc)
+	return sha256.Sum256(b)
+}
+
+// sample appends ids to the recorded ones, up to driftSampleSize
+func sample(recorded models.IDArray, ids []uint) models.IDArray {
+	for _, id := range ids {
+		if len(recorded) >= driftSampleSize {
+			break
+		}
+		recorded = append(recorded, id)
+	}
+	return recorded
+}
@@ -0,0 +1,86 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/susbuntu/blog-api/breaker"
+	"github.com/susbuntu/blog-api/models"
+)
+
+// failoverTimeout bounds each read from the primary index, so a slow cluster
+// falls back instead of holding up the request
+const failoverTimeout = 2 * time.Second
+
+// Failover reads from a primary SearchIndex behind a circuit breaker and
+// answers from the fallback whenever the primary fails or its breaker is
+// open. Writes only go to the primary and their errors are returned, so the
+// outbox keeps retrying them until the primary recovers; the fallback must
+// not depend on them. Without a fallback, read errors are returned as is.
+type Failover struct {
+	Primary  SearchIndex
+	Fallback SearchIndex
+	Breaker  *breaker.Breaker
+}
+
+func NewFailover(primary, fallback SearchIndex, b *breaker.Breaker) *Failover {
+	return &Failover{Primary: primary, Fallback: fallback, Breaker: b}
+}
+
+func (f *Failover) IndexPost(ctx context.Context, post models.Post) error {
+	return f.Breaker.Do(func() error {
+		return f.Primary.IndexPost(ctx, post)
+	})
+}
+
+func (f *Failover) DeletePost(ctx context.Context, postID uint) error {
+	return f.Breaker.Do(func() error {
+		return f.Primary.DeletePost(ctx, postID)
+	})
+}
+
+func (f *Failover) Search(ctx context.Context, query string, visibility models.PostVisibility, size int) (*SearchResults, error) {
+	var results *SearchResults
+	err := f.Breaker.Do(func() error {
+		ctx, cancel := context.WithTimeout(ctx, failoverTimeout)
+		defer cancel()
+
+		var err error
+		results, err = f.Primary.Search(ctx, query, visibility, size)
+		return err
+	})
+	if err == nil || !f.fallBack(err) {
+		return results, err
+	}
+	return f.Fallback.Search(ctx, query, visibility, size)
+}
+
+func (f *Failover) Related(ctx context.Context, post models.Post, visibility models.PostVisibility, size int) ([]uint, error) {
+	var ids []uint
+	err := f.Breaker.Do(func() error {
+		ctx, cancel := context.WithTimeout(ctx, failoverTimeout)
+		defer cancel()
+
+		var err error
+		ids, err = f.Primary.Related(ctx, post, visibility, size)
+		return err
+	})
+	if err == nil || !f.fallBack(err) {
+		return ids, err
+	}
+	return f.Fallback.Related(ctx, post, visibility, size)
+}
+
+// fallBack reports whether a failed read should be retried on the fallback,
+// logging the failure unless the breaker was already open
+func (f *Failover) fallBack(err error) bool {
+	if f.Fallback == nil {
+		return false
+	}
+	if !errors.Is(err, breaker.ErrOpen) {
+		log.Printf("Search index %s failed, falling back: %v", f.Breaker.Name, err)
+	}
+	return true
+}
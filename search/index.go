@@ -0,0 +1,166 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/olivere/elastic/v7"
+)
+
+// postsMapping is the mapping every new posts index is created with. Changing
+// it takes effect on the next reindex.
+var postsMapping = map[string]interface{}{
+	"properties": map[string]interface{}{
+		"id":             map[string]string{"type": "integer"},
+		"title":          map[string]string{"type": "text", "analyzer": "standard"},
+		"slug":           map[string]string{"type": "keyword"},
+		"content":        map[string]string{"type": "text", "analyzer": "standard"},
+		"content_format": map[string]string{"type": "keyword"},
+		"tags":           map[string]string{"type": "keyword"},
+		"author_id":      map[string]string{"type": "integer"},
+		"status":         map[string]string{"type": "keyword"},
+		"published_at":   map[string]string{"type": "date"},
+	},
+}
+
+// versionPattern matches the names of versioned posts indices
+var versionPattern = regexp.MustCompile(`^` + PostsIndex + `_v(\d+)$`)
+
+// IndexInfo describes one of the indices that has held posts
+type IndexInfo struct {
+	Name string `json:"name" example:"posts_v2"`
+	Docs int    `json:"docs" example:"1250"`
+	// Active is true for the index the posts alias points at
+	Active bool `json:"active" example:"true"`
+}
+
+// VersionedIndex names version n of the posts index
+func VersionedIndex(version int) string {
+	return fmt.Sprintf("%s_v%d", PostsIndex, version)
+}
+
+// EnsurePostsIndex makes sure the posts alias exists. A fresh cluster gets
+// posts_v1 behind it. Clusters from before indices were versioned have a
+// plain "posts" index instead, which keeps serving until a reindex replaces it.
+func EnsurePostsIndex(ctx context.Context, es *elastic.Client) error {
+	exists, err := es.IndexExists(PostsIndex).Do(ctx)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if legacy, err := hasLegacyIndex(es); err == nil && legacy {
+			log.Printf("Elasticsearch index %q is not versioned; run a reindex to move it behind an alias", PostsIndex)
+		}
+		return nil
+	}
+
+	body, err := indexBody(true)
+	if err != nil {
+		return err
+	}
+	if _, err := es.CreateIndex(VersionedIndex(1)).BodyString(body).Do(ctx); err != nil {
+		// Another replica may have created it at the same time
+		if exists, existsErr := es.IndexExists(PostsIndex).Do(ctx); existsErr == nil && exists {
+			return nil
+		}
+		return err
+	}
+
+	log.Printf("Created Elasticsearch index %s behind alias %s", VersionedIndex(1), PostsIndex)
+	return nil
+}
+
+// Indices lists the versioned posts indices, plus a legacy unversioned one if present
+func Indices(ctx context.Context, es *elastic.Client) ([]IndexInfo, error) {
+	active, err := aliasTargets(ctx, es)
+	if err != nil {
+		return nil, err
+	}
+	legacy, err := hasLegacyIndex(es)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := es.CatIndices().Index(PostsIndex+"*").Columns("index", "docs.count").Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var indices []IndexInfo
+	for _, row := range rows {
+		if !versionPattern.MatchString(row.Index) && !(legacy && row.Index == PostsIndex) {
+			continue
+		}
+		indices = append(indices, IndexInfo{
+			Name:   row.Index,
+			Docs:   row.DocsCount,
+			Active: contains(active, row.Index) || row.Index == PostsIndex,
+		})
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i].Name < indices[j].Name })
+	return indices, nil
+}
+
+// indexBody is the create-index request body, optionally putting the new index
+// behind the posts alias as its write index
+func indexBody(withAlias bool) (string, error) {
+	body := map[string]interface{}{"mappings": postsMapping}
+	if withAlias {
+		body["aliases"] = map[string]interface{}{
+			PostsIndex: map[string]bool{"is_write_index": true},
+		}
+	}
+
+	b, err := json.Marshal(body)
+	return string(b), err
+}
+
+// aliasTargets returns the indices the posts alias currently points at
+func aliasTargets(ctx context.Context, es *elastic.Client) ([]string, error) {
+	res, err := es.Aliases().Index("_all").Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return res.IndicesByAlias(PostsIndex), nil
+}
+
+// hasLegacyIndex reports whether "posts" is a concrete index rather than an alias
+func hasLegacyIndex(es *elastic.Client) (bool, error) {
+	names, err := es.IndexNames()
+	if err != nil {
+		return false, err
+	}
+	return contains(names, PostsIndex), nil
+}
+
+// nextVersion returns one more than the highest existing posts index version
+func nextVersion(es *elastic.Client) (int, error) {
+	names, err := es.IndexNames()
+	if err != nil {
+		return 0, err
+	}
+
+	latest := 0
+	for _, name := range names {
+		if m := versionPattern.FindStringSubmatch(name); m != nil {
+			if v, err := strconv.Atoi(m[1]); err == nil && v > latest {
+				latest = v
+			}
+		}
+	}
+	return latest + 1, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package search
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// startLocked takes the Postgres advisory lock lockID and runs job in the
+// background while holding it, then calls done with job's error. It returns
+// busy straight away if another session holds the lock, so only one replica
+// runs the job at a time.
+func startLocked(db *gorm.DB, lockID int64, name string, busy error, job func() error, done func(error)) error {
+	locked := make(chan error, 1)
+
+	go func() {
+		var ran, acquired bool
+		err := db.Connection(func(conn *gorm.DB) error {
+			ran = true
+
+			// Advisory locks belong to a session, so the lock is held on this connection throughout
+			if err := conn.Raw("SELECT pg_try_advisory_lock(?)", lockID).Scan(&acquired).Error; err != nil {
+				locked <- fmt.Errorf("acquire %s lock: %w", name, err)
+				return err
+			}
+			if !acquired {
+				locked <- busy
+				return nil
+			}
+			defer conn.Exec("SELECT pg_advisory_unlock(?)", lockID)
+			locked <- nil
+
+			return job()
+		})
+
+		// Without a connection the callback never ran and the caller is still waiting
+		if !ran {
+			locked <- fmt.Errorf("acquire %s lock: %w", name, err)
+			return
+		}
+
+		// Failing to get the lock has already been reported to the caller
+		if !acquired {
+			return
+		}
+		done(err)
+	}()
+
+	return <-locked
+}
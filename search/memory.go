@@ -0,0 +1,190 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/susbuntu/blog-api/models"
+)
+
+// Memory is a SearchIndex held in process memory. It approximates the
+// Elasticsearch queries closely enough for tests and single-instance local
+// runs: terms match case-insensitively with the same edit distance as
+// Fuzziness("AUTO"), and a document scores by its better-matching field.
+type Memory struct {
+	mu   sync.RWMutex
+	docs map[uint]models.PostSearchResult
+}
+
+func NewMemory() *Memory {
+	return &Memory{docs: make(map[uint]models.PostSearchResult)}
+}
+
+func (m *Memory) IndexPost(ctx context.Context, post models.Post) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.docs[post.ID] = NewPostDocument(post)
+	return nil
+}
+
+func (m *Memory) DeletePost(ctx context.Context, postID uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.docs, postID)
+	return nil
+}
+
+func (m *Memory) Search(ctx context.Context, query string, visibility models.PostVisibility, size int) (*SearchResults, error) {
+	started := time.Now()
+	terms := tokenize(query)
+
+	m.mu.RLock()
+	var hits []scoredDoc
+	for _, doc := range m.docs {
+		if !docVisible(doc, visibility) {
+			continue
+		}
+		// best_fields: the better of the two fields decides the score
+		score := matchScore(terms, tokenize(doc.Title))
+		if content := matchScore(terms, tokenize(doc.Content)); content > score {
+			score = content
+		}
+		if score > 0 {
+			hits = append(hits, scoredDoc{doc, score})
+		}
+	}
+	m.mu.RUnlock()
+
+	sortHits(hits)
+	results := &SearchResults{Posts: []models.PostSearchResult{}, Total: int64(len(hits)), Backend: "memory"}
+	for i := 0; i < len(hits) && i < size; i++ {
+		results.Posts = append(results.Posts, hits[i].doc)
+	}
+	results.TookInMillis = time.Since(started).Milliseconds()
+	return results, nil
+}
+
+func (m *Memory) Related(ctx context.Context, post models.Post, visibility models.PostVisibility, size int) ([]uint, error) {
+	tags := make(map[string]bool, len(post.Tags))
+	for _, tag := range post.Tags {
+		tags[tag] = true
+	}
+
+	m.mu.RLock()
+	var hits []scoredDoc
+	for _, doc := range m.docs {
+		if doc.ID == post.ID || !docVisible(doc, visibility) {
+			continue
+		}
+		shared := 0
+		for _, tag := range doc.Tags {
+			if tags[tag] {
+				shared++
+			}
+		}
+		if shared > 0 {
+			hits = append(hits, scoredDoc{doc, float64(shared)})
+		}
+	}
+	m.mu.RUnlock()
+
+	sortHits(hits)
+	var ids []uint
+	for i := 0; i < len(hits) && i < size; i++ {
+		ids = append(ids, hits[i].doc.ID)
+	}
+	return ids, nil
+}
+
+type scoredDoc struct {
+	doc   models.PostSearchResult
+	score float64
+}
+
+// sortHits orders hits by score, newest post first among equal scores
+func sortHits(hits []scoredDoc) {
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return hits[i].doc.ID > hits[j].doc.ID
+	})
+}
+
+func docVisible(doc models.PostSearchResult, visibility models.PostVisibility) bool {
+	return visibility.Allows(&models.Post{Status: doc.Status, AuthorID: doc.AuthorID})
+}
+
+// matchScore counts the query terms found in a field, weighting exact matches
+// above fuzzy ones
+func matchScore(terms, field []string) float64 {
+	score := 0.0
+	for _, term := range terms {
+		best := 0.0
+		for _, word := range field {
+			if word == term {
+				best = 1
+				break
+			}
+			if best == 0 && withinEdits(term, word, fuzziness(term)) {
+				best = 0.5
+			}
+		}
+		score += best
+	}
+	return score
+}
+
+// fuzziness is the edit distance Elasticsearch's AUTO setting allows for a term
+func fuzziness(term string) int {
+	switch n := len([]rune(term)); {
+	case n <= 2:
+		return 0
+	case n <= 5:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// withinEdits reports whether a and b are at most max single-rune edits apart
+func withinEdits(a, b string, max int) bool {
+	if max == 0 {
+		return a == b
+	}
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > max || -d > max {
+		return false
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)] <= max
+}
+
+// tokenize splits text into lowercase words, like the standard analyzer
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
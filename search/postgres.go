@@ -0,0 +1,86 @@
+package search
+
+import (
+	"context"
+	"time"
+
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// fullTextVector is the document Postgres full-text search matches posts by.
+// It must stay identical to the expression of idx_posts_fulltext.
+const fullTextVector = "to_tsvector('english', title || ' ' || content)"
+
+// Postgres is a SearchIndex that queries the posts table itself, so there is
+// nothing to keep in sync and IndexPost and DeletePost do nothing. Full-text
+// search matches stemmed English words with websearch_to_tsquery syntax rather
+// than fuzzily, and related posts are ranked by how many tags they share.
+type Postgres struct {
+	DB *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *Postgres {
+	return &Postgres{DB: db}
+}
+
+func (p *Postgres) IndexPost(ctx context.Context, post models.Post) error {
+	return nil
+}
+
+func (p *Postgres) DeletePost(ctx context.Context, postID uint) error {
+	return nil
+}
+
+func (p *Postgres) Search(ctx context.Context, query string, visibility models.PostVisibility, size int) (*SearchResults, error) {
+	started := time.Now()
+	matches := func() *gorm.DB {
+		return repository.Visible(p.DB.WithContext(ctx).Model(&models.Post{}), visibility).
+			Where(fullTextVector+" @@ websearch_to_tsquery('english', ?)", query)
+	}
+
+	var total int64
+	if err := matches().Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var posts []models.Post
+	err := matches().
+		Clauses(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "ts_rank(" + fullTextVector + ", websearch_to_tsquery('english', ?)) DESC, id DESC",
+			Vars: []interface{}{query},
+		}}).
+		Limit(size).
+		Find(&posts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	results := &SearchResults{Posts: make([]models.PostSearchResult, 0, len(posts)), Total: total, Backend: "postgres"}
+	for _, post := range posts {
+		results.Posts = append(results.Posts, NewPostDocument(post))
+	}
+	results.TookInMillis = time.Since(started).Milliseconds()
+	return results, nil
+}
+
+func (p *Postgres) Related(ctx context.Context, post models.Post, visibility models.PostVisibility, size int) ([]uint, error) {
+	if len(post.Tags) == 0 {
+		return nil, nil
+	}
+	tags := models.StringArray(post.Tags)
+
+	// Most shared tags first, using the GIN index on tags to find candidates
+	var ids []uint
+	err := repository.Visible(p.DB.WithContext(ctx).Model(&models.Post{}), visibility).
+		Where("tags && ?::text[] AND id <> ?", tags, post.ID).
+		Clauses(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "cardinality(ARRAY(SELECT unnest(tags) INTERSECT SELECT unnest(?::text[]))) DESC, created_at DESC",
+			Vars: []interface{}{tags},
+		}}).
+		Limit(size).
+		Pluck("id", &ids).Error
+	return ids, err
+}
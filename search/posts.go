@@ -0,0 +1,93 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/susbuntu/blog-api/models"
+)
+
+// PostsIndex is the alias post documents are read and written through. It
+// points at a versioned index such as posts_v2; see Reindex.
+const PostsIndex = "posts"
+
+// NewPostDocument builds the Elasticsearch document for a post
+func NewPostDocument(post models.Post) models.PostSearchResult {
+	return models.PostSearchResult{
+		ID:            post.ID,
+		Title:         post.Title,
+		Slug:          post.Slug,
+		Content:       post.Content,
+		ContentFormat: post.ContentFormat,
+		Tags:          []string(post.Tags),
+		AuthorID:      post.AuthorID,
+		Status:        post.Status,
+		PublishedAt:   post.PublishedAt,
+	}
+}
+
+// IndexPost creates or replaces a post's document
+func IndexPost(ctx context.Context, es *elastic.Client, post models.Post) error {
+	_, err := es.Index().
+		Index(PostsIndex).
+		Id(strconv.FormatUint(uint64(post.ID), 10)).
+		BodyJson(NewPostDocument(post)).
+		Do(ctx)
+	return err
+}
+
+// BulkIndexPosts creates or replaces the documents of many posts in one request
+func BulkIndexPosts(ctx context.Context, es *elastic.Client, posts []models.Post) error {
+	return bulkSync(ctx, es, PostsIndex, posts, nil)
+}
+
+// bulkSync indexes posts and deletes the documents of deletedIDs in one request.
+// Deleting a document that doesn't exist isn't an error.
+func bulkSync(ctx context.Context, es *elastic.Client, index string, posts []models.Post, deletedIDs []uint) error {
+	if len(posts) == 0 && len(deletedIDs) == 0 {
+		return nil
+	}
+
+	bulk := es.Bulk().Index(index)
+	for _, post := range posts {
+		bulk.Add(elastic.NewBulkIndexRequest().
+			Id(strconv.FormatUint(uint64(post.ID), 10)).
+			Doc(NewPostDocument(post)))
+	}
+	for _, id := range deletedIDs {
+		bulk.Add(elastic.NewBulkDeleteRequest().Id(strconv.FormatUint(uint64(id), 10)))
+	}
+
+	total := bulk.NumberOfActions()
+	res, err := bulk.Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	var failed []*elastic.BulkResponseItem
+	for _, item := range res.Failed() {
+		if item.Status != http.StatusNotFound {
+			failed = append(failed, item)
+		}
+	}
+	if len(failed) > 0 {
+		reason := "unknown"
+		if failed[0].Error != nil {
+			reason = failed[0].Error.Reason
+		}
+		return fmt.Errorf("%d of %d bulk operations failed, first: %s", len(failed), total, reason)
+	}
+	return nil
+}
+
+// DeletePost removes a post's document
+func DeletePost(ctx context.Context, es *elastic.Client, postID uint) error {
+	_, err := es.Delete().
+		Index(PostsIndex).
+		Id(strconv.FormatUint(uint64(postID), 10)).
+		Do(ctx)
+	return err
+}
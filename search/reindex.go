@@ -0,0 +1,273 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/susbuntu/blog-api/models"
+	"gorm.io/gorm"
+)
+
+// reindexBatchSize is how many posts are read from Postgres and bulk loaded at a time
+const reindexBatchSize = 500
+
+// reindexLockID is the Postgres advisory lock held while a reindex runs, so
+// only one replica rebuilds the index at a time
+const reindexLockID = 727311018
+
+// catchUpMargin widens the catch-up windows to cover clock skew between
+// replicas, since updated_at comes from the clock of whichever replica wrote it
+const catchUpMargin = time.Minute
+
+// ErrReindexRunning is returned when another reindex holds the lock
+var ErrReindexRunning = errors.New("a reindex is already running")
+
+// ReindexResult describes a completed reindex
+type ReindexResult struct {
+	Index    string        `json:"index" example:"posts_v3"`
+	Previous []string      `json:"previous" example:"posts_v2"`
+	Copied   int           `json:"copied" example:"1250"`
+	CaughtUp int           `json:"caught_up" example:"4"`
+	Pruned   int           `json:"pruned" example:"1"`
+	Duration time.Duration `json:"duration" swaggertype:"integer" example:"5230000000"`
+}
+
+// Reindex rebuilds the posts index from Postgres and waits for it to finish
+func Reindex(ctx context.Context, es *elastic.Client, db *gorm.DB) (*ReindexResult, error) {
+	type outcome struct {
+		result *ReindexResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+
+	err := StartReindex(ctx, es, db, func(result *ReindexResult, err error) {
+		done <- outcome{result, err}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	o := <-done
+	return o.result, o.err
+}
+
+// StartReindex takes the reindex lock and rebuilds the posts index in the
+// background, calling done with the outcome. It returns ErrReindexRunning
+// straight away if another reindex is in progress.
+//
+// The new index is loaded from Postgres while the current one keeps serving
+// reads and writes. Posts written during the copy are caught up from their
+// updated_at, the alias is swapped to the new index in one atomic request, and
+// writes that raced the swap are caught up once more. Documents of posts
+// purged during the copy are pruned at the end.
+func StartReindex(ctx context.Context, es *elastic.Client, db *gorm.DB, done func(*ReindexResult, error)) error {
+	var result *ReindexResult
+	return startLocked(db, reindexLockID, "reindex", ErrReindexRunning, func() error {
+		var err error
+		result, err = reindex(ctx, es, db)
+		return err
+	}, func(err error) {
+		if err != nil {
+			log.Printf("Reindex failed: %v", err)
+		}
+		done(result, err)
+	})
+}
+
+func reindex(ctx context.Context, es *elastic.Client, db *gorm.DB) (*ReindexResult, error) {
+	started := time.Now()
+
+	previous, err := aliasTargets(ctx, es)
+	if err != nil {
+		return nil, err
+	}
+	legacy, err := hasLegacyIndex(es)
+	if err != nil {
+		return nil, err
+	}
+	version, err := nextVersion(es)
+	if err != nil {
+		return nil, err
+	}
+
+	index := VersionedIndex(version)
+	result := &ReindexResult{Index: index, Previous: previous}
+	if legacy {
+		result.Previous = append(result.Previous, PostsIndex)
+	}
+
+	body, err := indexBody(false)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := es.CreateIndex(index).BodyString(body).Do(ctx); err != nil {
+		return nil, fmt.Errorf("create %s: %w", index, err)
+	}
+	log.Printf("Reindexing posts into %s", index)
+
+	swapped := false
+	defer func() {
+		// Don't leave a half-built index behind if anything before the swap failed
+		if !swapped {
+			if _, err := es.DeleteIndex(index).Do(context.Background()); err != nil {
+				log.Printf("Failed to delete abandoned index %s: %v", index, err)
+			}
+		}
+	}()
+
+	copyStarted := time.Now().Add(-catchUpMargin)
+	if result.Copied, err = copyPosts(ctx, es, db, index); err != nil {
+		return nil, fmt.Errorf("copy posts: %w", err)
+	}
+
+	catchUpStarted := time.Now().Add(-catchUpMargin)
+	caughtUp, err := catchUp(ctx, es, db, index, copyStarted)
+	if err != nil {
+		return nil, fmt.Errorf("catch up: %w", err)
+	}
+	result.CaughtUp += caughtUp
+
+	// Make everything searchable before readers are switched over
+	if _, err := es.Refresh(index).Do(ctx); err != nil {
+		return nil, fmt.Errorf("refresh %s: %w", index, err)
+	}
+
+	// Readers and writers move to the new index in a single request
+	actions := []elastic.AliasAction{elastic.NewAliasAddAction(PostsIndex).Index(index).IsWriteIndex(true)}
+	for _, old := range previous {
+		actions = append(actions, elastic.NewAliasRemoveAction(PostsIndex).Index(old))
+	}
+	if legacy {
+		// An alias can't share its name with an index, so the legacy index goes in the same request
+		actions = append(actions, elastic.NewAliasRemoveIndexAction(PostsIndex))
+	}
+	if _, err := es.Alias().Action(actions...).Do(ctx); err != nil {
+		return nil, fmt.Errorf("swap alias: %w", err)
+	}
+	swapped = true
+	log.Printf("Alias %s now points at %s", PostsIndex, index)
+
+	// Writes between the first catch-up and the swap went to the old index
+	caughtUp, err = catchUp(ctx, es, db, index, catchUpStarted)
+	if err != nil {
+		return result, fmt.Errorf("catch up after swap: %w", err)
+	}
+	result.CaughtUp += caughtUp
+
+	if result.Pruned, err = prune(ctx, es, db, index); err != nil {
+		return result, fmt.Errorf("prune: %w", err)
+	}
+
+	for _, old := range previous {
+		if _, err := es.DeleteIndex(old).Do(ctx); err != nil {
+			log.Printf("Failed to delete old index %s: %v", old, err)
+		}
+	}
+
+	result.Duration = time.Since(started)
+	log.Printf("Reindex into %s completed: %d copied, %d caught up, %d pruned in %s",
+		index, result.Copied, result.CaughtUp, result.Pruned, result.Duration)
+	return result, nil
+}
+
+// copyPosts bulk loads every live post into index
+func copyPosts(ctx context.Context, es *elastic.Client, db *gorm.DB, index string) (int, error) {
+	copied := 0
+	var posts []models.Post
+	err := db.WithContext(ctx).Order("id ASC").FindInBatches(&posts, reindexBatchSize, func(tx *gorm.DB, batch int) error {
+		if err := bulkSync(ctx, es, index, posts, nil); err != nil {
+			return err
+		}
+		copied += len(posts)
+		return nil
+	}).Error
+	return copied, err
+}
+
+// catchUp re-syncs posts changed since the given time: live posts are
+// indexed again and trashed ones removed
+func catchUp(ctx context.Context, es *elastic.Client, db *gorm.DB, index string, since time.Time) (int, error) {
+	synced := 0
+	var posts []models.Post
+	err := db.WithContext(ctx).Unscoped().
+		Where("updated_at >= ? OR deleted_at >= ?", since, since).
+		Order("id ASC").
+		FindInBatches(&posts, reindexBatchSize, func(tx *gorm.DB, batch int) error {
+			var live []models.Post
+			var trashed []uint
+			for _, post := range posts {
+				if post.DeletedAt.Valid {
+					trashed = append(trashed, post.ID)
+				} else {
+					live = append(live, post)
+				}
+			}
+
+			if err := bulkSync(ctx, es, index, live, trashed); err != nil {
+				return err
+			}
+			synced += len(posts)
+			return nil
+		}).Error
+	return synced, err
+}
+
+// prune deletes documents whose posts no longer exist, such as posts purged
+// while the copy was running
+func prune(ctx context.Context, es *elastic.Client, db *gorm.DB, index string) (int, error) {
+	scroll := es.Scroll(index).FetchSource(false).Size(reindexBatchSize)
+	defer scroll.Clear(context.Background())
+
+	pruned := 0
+	for {
+		res, err := scroll.Do(ctx)
+		if err == io.EOF {
+			return pruned, nil
+		}
+		if err != nil {
+			return pruned, err
+		}
+
+		ids := make([]uint, 0, len(res.Hits.Hits))
+		for _, hit := range res.Hits.Hits {
+			if id, err := strconv.ParseUint(hit.Id, 10, 64); err == nil {
+				ids = append(ids, uint(id))
+			}
+		}
+
+		found, err := livePostIDs(ctx, db, ids)
+		if err != nil {
+			return pruned, err
+		}
+
+		var missing []uint
+		for _, id := range ids {
+			if !found[id] {
+				missing = append(missing, id)
+			}
+		}
+		if err := bulkSync(ctx, es, index, nil, missing); err != nil {
+			return pruned, err
+		}
+		pruned += len(missing)
+	}
+}
+
+// livePostIDs returns which of ids belong to posts that exist and aren't trashed
+func livePostIDs(ctx context.Context, db *gorm.DB, ids []uint) (map[uint]bool, error) {
+	var existing []uint
+	if err := db.WithContext(ctx).Model(&models.Post{}).Where("id IN ?", ids).Pluck("id", &existing).Error; err != nil {
+		return nil, err
+	}
+	found := make(map[uint]bool, len(existing))
+	for _, id := range existing {
+		found[id] = true
+	}
+	return found, nil
+}
@@ -0,0 +1,151 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/susbuntu/blog-api/models"
+	"gorm.io/gorm"
+)
+
+// SearchIndex holds the search documents of live posts
+type SearchIndex interface {
+	// IndexPost creates or replaces a post's document
+	IndexPost(ctx context.Context, post models.Post) error
+	// DeletePost removes a post's document; a missing document isn't an error
+	DeletePost(ctx context.Context, postID uint) error
+	// Search runs a full-text query over titles and content, best matches first
+	Search(ctx context.Context, query string, visibility models.PostVisibility, size int) (*SearchResults, error)
+	// Related returns the IDs of posts sharing tags with post, most shared tags first
+	Related(ctx context.Context, post models.Post, visibility models.PostVisibility, size int) ([]uint, error)
+}
+
+// SearchResults is one page of full-text search hits
+type SearchResults struct {
+	Posts []models.PostSearchResult
+	// Total counts every match, not just the returned page
+	Total int64
+	// TookInMillis is how long the search itself took
+	TookInMillis int64
+	// Backend names the index that answered, which differs from the
+	// configured one while Elasticsearch is failed over
+	Backend string
+}
+
+// Elasticsearch is a SearchIndex backed by the posts alias of an Elasticsearch cluster
+type Elasticsearch struct {
+	Client *elastic.Client
+}
+
+func NewElasticsearch(client *elastic.Client) *Elasticsearch {
+	return &Elasticsearch{Client: client}
+}
+
+func (e *Elasticsearch) IndexPost(ctx context.Context, post models.Post) error {
+	return IndexPost(ctx, e.Client, post)
+}
+
+func (e *Elasticsearch) DeletePost(ctx context.Context, postID uint) error {
+	err := DeletePost(ctx, e.Client, postID)
+	if elastic.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func (e *Elasticsearch) Search(ctx context.Context, query string, visibility models.PostVisibility, size int) (*SearchResults, error) {
+	searchQuery := elastic.NewBoolQuery().Must(
+		elastic.NewMultiMatchQuery(query, "title", "content").
+			Type("best_fields").
+			Fuzziness("AUTO"),
+	)
+	if filter := visibilityFilter(visibility); filter != nil {
+		searchQuery = searchQuery.Filter(filter)
+	}
+
+	res, err := e.Client.Search().
+		Index(PostsIndex).
+		Query(searchQuery).
+		Size(size).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := &SearchResults{Posts: []models.PostSearchResult{}, Total: res.Hits.TotalHits.Value, TookInMillis: res.TookInMillis, Backend: "elasticsearch"}
+	for _, hit := range res.Hits.Hits {
+		var post models.PostSearchResult
+		if err := json.Unmarshal(hit.Source, &post); err == nil {
+			results.Posts = append(results.Posts, post)
+		}
+	}
+	return results, nil
+}
+
+func (e *Elasticsearch) Related(ctx context.Context, post models.Post, visibility models.PostVisibility, size int) ([]uint, error) {
+	if len(post.Tags) == 0 {
+		return nil, nil
+	}
+
+	// At least one of the post's tags, excluding the post itself
+	boolQuery := elastic.NewBoolQuery()
+	for _, tag := range post.Tags {
+		boolQuery = boolQuery.Should(elastic.NewTermQuery("tags", tag))
+	}
+	boolQuery = boolQuery.MustNot(elastic.NewTermQuery("id", post.ID)).MinimumShouldMatch("1")
+	if filter := visibilityFilter(visibility); filter != nil {
+		boolQuery = boolQuery.Filter(filter)
+	}
+
+	res, err := e.Client.Search().
+		Index(PostsIndex).
+		Query(boolQuery).
+		Size(size).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint
+	for _, hit := range res.Hits.Hits {
+		var doc models.PostSearchResult
+		if err := json.Unmarshal(hit.Source, &doc); err == nil {
+			ids = append(ids, doc.ID)
+		}
+	}
+	return ids, nil
+}
+
+// visibilityFilter restricts a query to the posts visible to a reader. It
+// returns nil when every post is visible.
+func visibilityFilter(visibility models.PostVisibility) elastic.Query {
+	if visibility.All {
+		return nil
+	}
+
+	published := elastic.NewTermQuery("status", models.PostPublished)
+	if visibility.AuthorID == nil {
+		return published
+	}
+	return elastic.NewBoolQuery().
+		Should(published, elastic.NewTermQuery("author_id", *visibility.AuthorID)).
+		MinimumShouldMatch("1")
+}
+
+// Fill indexes every live post into index. An in-memory index starts empty, so
+// it is filled from Postgres on startup.
+func Fill(ctx context.Context, index SearchIndex, db *gorm.DB) (int, error) {
+	filled := 0
+	var posts []models.Post
+	err := db.WithContext(ctx).Order("id ASC").FindInBatches(&posts, reindexBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, post := range posts {
+			if err := index.IndexPost(ctx, post); err != nil {
+				return err
+			}
+		}
+		filled += len(posts)
+		return nil
+	}).Error
+	return filled, err
+}
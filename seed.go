@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/susbuntu/blog-api/cache"
+	"github.com/susbuntu/blog-api/config"
+	"github.com/susbuntu/blog-api/database"
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/render"
+	"github.com/susbuntu/blog-api/search"
+	"github.com/susbuntu/blog-api/slug"
+	"gorm.io/gorm"
+)
+
+const demoEmail = "demo@example.com"
+
+// demoPosts are published by the demo author, newest first
+var demoPosts = []struct {
+	Title   string
+	Content string
+	Tags    []string
+}{
+	{
+		Title:   "Welcome to the blog",
+		Content: "This post was created by `blog-api seed`.\n\nPosts are written in **Markdown**, rendered to sanitized HTML and searchable through Elasticsearch.",
+		Tags:    []string{"meta"},
+	},
+	{
+		Title:   "Getting started with Go",
+		Content: "Go is a small language with a big standard library.\n\n```go\nfmt.Println(\"hello, world\")\n```\n\nStart with the tour, then build something.",
+		Tags:    []string{"golang", "programming", "tutorial"},
+	},
+	{
+		Title:   "Caching with Redis",
+		Content: "The cache-aside pattern keeps reads fast:\n\n1. Look the key up in Redis\n2. On a miss, load from Postgres\n3. Store the result with a TTL\n\nWrites invalidate the key.",
+		Tags:    []string{"redis", "caching", "programming"},
+	},
+	{
+		Title:   "Full-text search with Elasticsearch",
+		Content: "Elasticsearch scores documents by relevance and tolerates typos with fuzzy matching.\n\n| Field | Type |\n|-------|------|\n| title | text |\n| tags | keyword |",
+		Tags:    []string{"elasticsearch", "search"},
+	},
+	{
+		Title:   "Indexing arrays in PostgreSQL",
+		Content: "A GIN index makes `tags @> ARRAY['golang']` fast even on large tables.",
+		Tags:    []string{"postgresql", "programming"},
+	},
+}
+
+// runSeed handles the "seed" command. Posts the demo author already has are
+// skipped, so seeding twice is harmless.
+func runSeed(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	password := flags.String("password", "", "Password for the demo author; a random one is generated and printed if omitted")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return errors.New("usage: seed [-password <password>]")
+	}
+
+	db, err := openMigratedDB(cfg)
+	if err != nil {
+		return err
+	}
+	rdb := database.InitRedis(cfg)
+	es := database.InitElasticsearch(cfg)
+	ctx := context.Background()
+
+	author, err := demoAuthor(db, *password)
+	if err != nil {
+		return err
+	}
+
+	var created []models.Post
+	var tags []string
+	now := time.Now()
+	for i, demo := range demoPosts {
+		var existing int64
+		if err := db.Model(&models.Post{}).Where("author_id = ? AND title = ?", author.ID, demo.Title).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			continue
+		}
+
+		publishedAt := now.Add(-time.Duration(i) * 24 * time.Hour)
+		post := models.Post{
+			Title:         demo.Title,
+			Content:       demo.Content,
+			ContentFormat: render.FormatMarkdown,
+			Tags:          models.StringArray(demo.Tags),
+			AuthorID:      &author.ID,
+			Status:        models.PostPublished,
+			PublishedAt:   &publishedAt,
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			postSlug, err := slug.ForPost(tx, post.Title, 0)
+			if err != nil {
+				return err
+			}
+			post.Slug = postSlug
+
+			if err := tx.Create(&post).Error; err != nil {
+				return err
+			}
+
+			revision := models.PostRevision{
+				PostID:        post.ID,
+				Revision:      1,
+				Title:         post.Title,
+				Content:       post.Content,
+				ContentFormat: post.ContentFormat,
+				Tags:          post.Tags,
+				EditorID:      &author.ID,
+			}
+			if err := tx.Create(&revision).Error; err != nil {
+				return err
+			}
+			return tx.Create(&models.ActivityLog{Action: "new_post", PostID: &post.ID}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("create %q: %w", demo.Title, err)
+		}
+
+		created = append(created, post)
+		tags = append(tags, demo.Tags...)
+	}
+
+	if len(created) == 0 {
+		fmt.Println("Demo posts already exist")
+		return nil
+	}
+
+	// Feeds are cached, so drop the ones the new posts appear in
+	rdb.Del(ctx, cache.FeedKeys(tags...)...)
+
+	if err := search.BulkIndexPosts(ctx, es, created); err != nil {
+		return fmt.Errorf("index demo posts: %w", err)
+	}
+
+	log.Printf("Seeded %d demo posts by %s", len(created), author.Email)
+	return nil
+}
+
+// demoAuthor returns the demo account, creating it on the first run
+func demoAuthor(db *gorm.DB, password string) (models.User, error) {
+	var author models.User
+	err := db.Where("email = ?", demoEmail).First(&author).Error
+	if err == nil {
+		return author, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.User{}, err
+	}
+
+	generated := password == ""
+	if generated {
+		if password, err = randomPassword(); err != nil {
+			return models.User{}, err
+		}
+	}
+
+	author, err = createUser(db, demoEmail, "Demo Author", models.RoleAuthor, password)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	fmt.Printf("Created demo author %s\n", author.Email)
+	if generated {
+		fmt.Printf("Password: %s\n", password)
+	}
+	return author, nil
+}
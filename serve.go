@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/susbuntu/blog-api/breaker"
+	"github.com/susbuntu/blog-api/cache"
+	"github.com/susbuntu/blog-api/config"
+	"github.com/susbuntu/blog-api/database"
+	"github.com/susbuntu/blog-api/handlers"
+	"github.com/susbuntu/blog-api/health"
+	"github.com/susbuntu/blog-api/repository"
+	"github.com/susbuntu/blog-api/routes"
+	"github.com/susbuntu/blog-api/search"
+	"github.com/susbuntu/blog-api/storage"
+	"github.com/susbuntu/blog-api/tracing"
+	"github.com/susbuntu/blog-api/workers"
+
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+// runServe handles the "serve" command
+func runServe(cfg *config.Config, args []string) error {
+	if len(args) > 0 {
+		return errors.New("serve takes no arguments")
+	}
+	if cfg.JWT.Secret == config.DefaultJWTSecret {
+		log.Println("WARNING: JWT_SECRET is not set, using the development default")
+	}
+
+	// Start tracing before the backends so their clients are instrumented
+	shutdownTracing, err := tracing.Init(cfg.Tracing)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing(context.Background())
+
+	// Initialize the configured backends
+	stores, err := openStores(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Start background workers. They all work through Postgres tables, so
+	// they don't run on memory storage.
+	db := stores.DB
+	if db != nil && cfg.Scheduler.Enabled {
+		scheduler := workers.NewScheduler(db, stores.Cache, cfg.Scheduler.Interval)
+		go scheduler.Run(context.Background())
+	}
+	if db != nil && cfg.Outbox.Enabled {
+		relay := workers.NewOutboxRelay(db, stores.Search, cfg.Outbox)
+		go relay.Run(context.Background())
+	}
+	if db != nil && stores.ES != nil && cfg.Consistency.Enabled {
+		checker := workers.NewConsistencyChecker(db, stores.ES, cfg.Consistency)
+		go checker.Run(context.Background())
+	}
+	if db != nil && cfg.Thumbnails.Enabled {
+		thumbnailer := workers.NewThumbnailer(db, stores.Cache, storage.NewLocalStore(cfg.Media.Dir), cfg.Thumbnails)
+		go thumbnailer.Run(context.Background())
+	}
+
+	// Initialize Gin router
+	router := gin.Default()
+
+	// Setup routes
+	routes.SetupRoutes(router, cfg, stores)
+
+	// Swagger endpoint
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	// Start server
+	log.Printf("Server starting on port %s", cfg.Port)
+	log.Printf("Swagger documentation available at: http://localhost:%s/swagger/index.html", cfg.Port)
+	return router.Run(":" + cfg.Port)
+}
+
+// openStores connects the backends picked by STORAGE_BACKEND, CACHE_BACKEND
+// and SEARCH_BACKEND. Redis and Elasticsearch sit behind circuit breakers:
+// while the cache is down reads go to the database, and while Elasticsearch
+// is down search is answered from Postgres when storage is Postgres. An
+// in-memory search index in front of Postgres starts empty, so it is filled
+// from the posts table first.
+func openStores(cfg *config.Config) (handlers.Stores, error) {
+	var stores handlers.Stores
+
+	switch cfg.Backends.Storage {
+	case "postgres":
+		db, err := openMigratedDB(cfg)
+		if err != nil {
+			return stores, err
+		}
+		stores.DB = db
+		stores.Checks = append(stores.Checks, health.Postgres(db))
+	case "memory":
+		log.Println("WARNING: using in-memory storage, nothing is kept across restarts")
+	default:
+		return stores, fmt.Errorf("unknown STORAGE_BACKEND %q", cfg.Backends.Storage)
+	}
+
+	switch cfg.Backends.Cache {
+	case "redis":
+		rdb := database.InitRedis(cfg)
+		redisBreaker := breaker.New("redis", cfg.Breaker.Threshold, cfg.Breaker.Cooldown)
+		stores.Cache = cache.NewGuarded(cache.NewRedis(rdb), redisBreaker)
+		stores.Breakers = append(stores.Breakers, redisBreaker)
+		stores.Checks = append(stores.Checks, health.Redis(rdb))
+	case "memory":
+		stores.Cache = cache.NewMemory()
+	default:
+		return stores, fmt.Errorf("unknown CACHE_BACKEND %q", cfg.Backends.Cache)
+	}
+
+	switch cfg.Backends.Search {
+	case "elasticsearch":
+		var fallback search.SearchIndex
+		if stores.DB != nil {
+			fallback = search.NewPostgres(stores.DB)
+		}
+		esBreaker := breaker.New("elasticsearch", cfg.Breaker.Threshold, cfg.Breaker.Cooldown)
+		stores.ES = database.InitElasticsearch(cfg)
+		stores.Search = search.NewFailover(search.NewElasticsearch(stores.ES), fallback, esBreaker)
+		stores.Breakers = append(stores.Breakers, esBreaker)
+		stores.Checks = append(stores.Checks, health.Elasticsearch(stores.ES, database.ElasticsearchURL(cfg)))
+	case "memory":
+		stores.Search = search.NewMemory()
+	default:
+		return stores, fmt.Errorf("unknown SEARCH_BACKEND %q", cfg.Backends.Search)
+	}
+
+	if stores.DB != nil {
+		stores.Posts = repository.NewPostgresPosts(stores.DB)
+		stores.ActivityLogs = repository.NewPostgresActivityLogs(stores.DB)
+		stores.Users = repository.NewPostgresUsers(stores.DB)
+
+		if stores.ES == nil {
+			filled, err := search.Fill(context.Background(), stores.Search, stores.DB)
+			if err != nil {
+				return stores, fmt.Errorf("fill search index: %w", err)
+			}
+			log.Printf("Loaded %d posts into the in-memory search index", filled)
+		}
+	} else {
+		logs := repository.NewMemoryActivityLogs()
+		stores.Posts = repository.NewMemoryPosts(logs, stores.Search)
+		stores.ActivityLogs = logs
+		stores.Users = repository.NewMemoryUsers()
+	}
+
+	return stores, nil
+}
@@ -0,0 +1,72 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+// MaxURLs is the most entries the sitemap protocol allows in one file;
+// larger sites have to split their URLs across a sitemap index
+const MaxURLs = 50000
+
+const namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// Writer streams a <urlset> or <sitemapindex> document entry by entry, so
+// sitemaps of any size can be written without holding them in memory
+type Writer struct {
+	enc   *xml.Encoder
+	root  xml.StartElement
+	entry string
+}
+
+type entry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// NewURLSet starts a sitemap listing page URLs
+func NewURLSet(w io.Writer) (*Writer, error) {
+	return newWriter(w, "urlset", "url")
+}
+
+// NewIndex starts a sitemap index listing child sitemaps
+func NewIndex(w io.Writer) (*Writer, error) {
+	return newWriter(w, "sitemapindex", "sitemap")
+}
+
+func newWriter(w io.Writer, root, entry string) (*Writer, error) {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return nil, err
+	}
+
+	sw := &Writer{
+		enc: xml.NewEncoder(w),
+		root: xml.StartElement{
+			Name: xml.Name{Local: root},
+			Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: namespace}},
+		},
+		entry: entry,
+	}
+	if err := sw.enc.EncodeToken(sw.root); err != nil {
+		return nil, err
+	}
+	return sw, nil
+}
+
+// Add writes one entry. A zero lastMod is left out.
+func (w *Writer) Add(loc string, lastMod time.Time) error {
+	e := entry{Loc: loc}
+	if !lastMod.IsZero() {
+		e.LastMod = lastMod.UTC().Format(time.RFC3339)
+	}
+	return w.enc.EncodeElement(e, xml.StartElement{Name: xml.Name{Local: w.entry}})
+}
+
+// Close ends the document and flushes it to the underlying writer
+func (w *Writer) Close() error {
+	if err := w.enc.EncodeToken(w.root.End()); err != nil {
+		return err
+	}
+	return w.enc.Flush()
+}
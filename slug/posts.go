@@ -0,0 +1,70 @@
+package slug
+
+import (
+	"github.com/susbuntu/blog-api/models"
+	"gorm.io/gorm"
+)
+
+// ForPost returns a unique slug for a post with the given title. A slug counts
+// as taken while any other post uses it, trashed posts and old redirect slugs
+// included, so a link never silently starts pointing at a different post.
+// postID is the post being named, or 0 for a new post.
+func ForPost(tx *gorm.DB, title string, postID uint) (string, error) {
+	return Unique(Make(title), func(candidate string) (bool, error) {
+		var count int64
+		err := tx.Unscoped().Model(&models.Post{}).
+			Where("slug = ? AND id <> ?", candidate, postID).
+			Count(&count).Error
+		if err != nil || count > 0 {
+			return count > 0, err
+		}
+
+		err = tx.Model(&models.PostSlug{}).
+			Where("slug = ? AND post_id <> ?", candidate, postID).
+			Count(&count).Error
+		return count > 0, err
+	})
+}
+
+// Backfill gives posts without a slug one, oldest first so earlier posts get
+// the unsuffixed slug, and returns how many it named. Posts from before slugs
+// existed are the only ones without.
+func Backfill(db *gorm.DB) (int, error) {
+	var posts []models.Post
+	if err := db.Unscoped().Where("slug IS NULL OR slug = ''").Order("id ASC").Find(&posts).Error; err != nil {
+		return 0, err
+	}
+
+	for _, post := range posts {
+		postSlug, err := ForPost(db, post.Title, post.ID)
+		if err != nil {
+			return 0, err
+		}
+		if err := db.Unscoped().Model(&post).UpdateColumn("slug", postSlug).Error; err != nil {
+			return 0, err
+		}
+	}
+	return len(posts), nil
+}
+
+// Rename gives the post a slug for its current title and keeps the previous
+// slug as a redirect. Returning to an earlier title reclaims that slug from the
+// post's redirects. It is a no-op when the title still maps to the same slug.
+func Rename(tx *gorm.DB, post *models.Post) error {
+	next, err := ForPost(tx, post.Title, post.ID)
+	if err != nil || next == post.Slug {
+		return err
+	}
+
+	if err := tx.Where("slug = ? AND post_id = ?", next, post.ID).Delete(&models.PostSlug{}).Error; err != nil {
+		return err
+	}
+	if post.Slug != "" {
+		if err := tx.Create(&models.PostSlug{Slug: post.Slug, PostID: post.ID}).Error; err != nil {
+			return err
+		}
+	}
+
+	post.Slug = next
+	return nil
+}
@@ -0,0 +1,66 @@
+package slug
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mozillazg/go-unidecode"
+)
+
+// MaxLength caps slug length so URLs stay readable; longer titles are cut at a word boundary
+const MaxLength = 80
+
+// fallback is used when a title has nothing that transliterates to letters or digits
+const fallback = "post"
+
+// Make turns a title into a lowercase, hyphen-separated ASCII slug.
+// Non-ASCII text is transliterated first, so "Crème Brûlée" becomes
+// "creme-brulee" and "Привет мир" becomes "privet-mir".
+func Make(title string) string {
+	ascii := strings.ToLower(unidecode.Unidecode(title))
+
+	var b strings.Builder
+	hyphen := false
+	for _, r := range ascii {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(r)
+		case r == '\'':
+			// Drop apostrophes so "what's" becomes "whats" rather than "what-s"
+		default:
+			hyphen = true
+		}
+	}
+
+	s := b.String()
+	if len(s) > MaxLength {
+		s = s[:MaxLength]
+		if i := strings.LastIndexByte(s, '-'); i > 0 {
+			s = s[:i]
+		}
+	}
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+// Unique returns base if it is free, otherwise the first of base-2, base-3, ...
+// that taken reports as unused
+func Unique(base string, taken func(candidate string) (bool, error)) (string, error) {
+	candidate := base
+	for n := 2; ; n++ {
+		used, err := taken(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !used {
+			return candidate, nil
+		}
+		candidate = base + "-" + strconv.Itoa(n)
+	}
+}
@@ -0,0 +1,97 @@
+package spam
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Input is what a Scorer looks at when judging a comment
+type Input struct {
+	Body string
+
+	// RecentBodies are the author's most recent comments, used to spot copy-paste spam
+	RecentBodies []string
+}
+
+// Result is a spam score between 0 (clean) and 1 (certainly spam) with the reasons behind it
+type Result struct {
+	Score   float64
+	Reasons []string
+}
+
+// Scorer decides how likely a comment is to be spam. Implementations can wrap
+// external services; Heuristic is the built-in default.
+type Scorer interface {
+	Score(in Input) Result
+}
+
+var (
+	linkPattern = regexp.MustCompile(`(?i)(https?://|www\.)\S+`)
+	wordPattern = regexp.MustCompile(`[\p{L}\p{N}']+`)
+)
+
+// Heuristic scores comments on link count, repeated content and blocklisted words
+type Heuristic struct {
+	MaxLinks  int
+	Blocklist []string
+}
+
+func NewHeuristic(maxLinks int, blocklist []string) *Heuristic {
+	words := make([]string, 0, len(blocklist))
+	for _, w := range blocklist {
+		if w = strings.ToLower(strings.TrimSpace(w)); w != "" {
+			words = append(words, w)
+		}
+	}
+	return &Heuristic{MaxLinks: maxLinks, Blocklist: words}
+}
+
+func (h *Heuristic) Score(in Input) Result {
+	var result Result
+	body := strings.ToLower(in.Body)
+
+	// Links beyond the allowance are the strongest signal for drive-by spam
+	if links := len(linkPattern.FindAllString(body, -1)); links > h.MaxLinks {
+		result.add(0.4+0.1*float64(links-h.MaxLinks-1), "too many links")
+	}
+
+	for _, word := range h.Blocklist {
+		if strings.Contains(body, word) {
+			result.add(0.5, "contains blocklisted word \""+word+"\"")
+		}
+	}
+
+	// The same comment posted again, possibly on another post
+	normalized := normalize(in.Body)
+	for _, recent := range in.RecentBodies {
+		if normalized != "" && normalize(recent) == normalized {
+			result.add(0.6, "duplicate of a recent comment")
+			break
+		}
+	}
+
+	// A handful of words repeated over and over
+	if words := wordPattern.FindAllString(body, -1); len(words) >= 10 {
+		unique := make(map[string]struct{}, len(words))
+		for _, w := range words {
+			unique[w] = struct{}{}
+		}
+		if float64(len(unique))/float64(len(words)) < 0.3 {
+			result.add(0.3, "highly repetitive content")
+		}
+	}
+
+	return result
+}
+
+func (r *Result) add(score float64, reason string) {
+	r.Score += score
+	if r.Score > 1 {
+		r.Score = 1
+	}
+	r.Reasons = append(r.Reasons, reason)
+}
+
+func normalize(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
@@ -0,0 +1,21 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when a blob doesn't exist
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores opaque files under string keys. Keys are slash-separated
+// paths chosen by the caller, such as "media/ab/abcdef...".
+type BlobStore interface {
+	// Put writes the reader's content under key, replacing any existing blob
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Open returns the blob's content; callers must close it
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,104 @@
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png" // registers the PNG decoder
+	"io"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // registers the WebP decoder
+)
+
+// Output formats
+const (
+	FormatJPEG = "jpeg"
+	FormatWebP = "webp"
+)
+
+// mimeTypes maps output formats to the MIME type of the encoded variant
+var mimeTypes = map[string]string{
+	FormatJPEG: "image/jpeg",
+	FormatWebP: "image/webp",
+}
+
+// Resizable lists the source MIME types thumbnails can be made from. GIFs are
+// left alone so animations aren't flattened to their first frame.
+var Resizable = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+// MIMEType returns the MIME type of variants encoded in format, or "" if the
+// format isn't supported
+func MIMEType(format string) string {
+	return mimeTypes[format]
+}
+
+// ErrTooLarge is returned by Decode for images with more pixels than allowed
+var ErrTooLarge = errors.New("image dimensions exceed the pixel limit")
+
+// Decode reads an image in any of the Resizable formats. The header is checked
+// first so an image declaring more than maxPixels pixels is rejected before
+// anything is allocated for it; maxPixels <= 0 means no limit.
+func Decode(r io.Reader, maxPixels int) (image.Image, error) {
+	var header bytes.Buffer
+	cfg, _, err := image.DecodeConfig(io.TeeReader(r, &header))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, fmt.Errorf("invalid image dimensions %dx%d", cfg.Width, cfg.Height)
+	}
+	if maxPixels > 0 && int64(cfg.Width)*int64(cfg.Height) > int64(maxPixels) {
+		return nil, fmt.Errorf("%w: %dx%d", ErrTooLarge, cfg.Width, cfg.Height)
+	}
+
+	img, _, err := image.Decode(io.MultiReader(&header, r))
+	return img, err
+}
+
+// Resize scales img to the given width, keeping its aspect ratio
+func Resize(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// Encode writes img in the given format. WebP output is lossless, as there is
+// no pure Go lossy encoder; quality only applies to JPEG.
+func Encode(w io.Writer, img image.Image, format string, quality int) error {
+	switch format {
+	case FormatJPEG:
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+	case FormatWebP:
+		return nativewebp.Encode(w, img, nil)
+	default:
+		return fmt.Errorf("unsupported thumbnail format %q", format)
+	}
+}
+
+// VariantName names the variant of the given width and format, such as "640w.webp"
+func VariantName(width int, format string) string {
+	ext := format
+	if format == FormatJPEG {
+		ext = "jpg"
+	}
+	return fmt.Sprintf("%dw.%s", width, ext)
+}
+
+// VariantKey is the blob key a variant is stored under, next to its original
+func VariantKey(originalKey, name string) string {
+	return originalKey + "." + name
+}
@@ -0,0 +1,79 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// querySpanKey is where the GORM callbacks keep a statement's span
+const querySpanKey = "tracing:query_span"
+
+// InstrumentGORM records a span for every query run through db within a trace
+func InstrumentGORM(db *gorm.DB) error {
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("gorm:create").Register("tracing:before_create", startQuery("create")),
+		callbacks.Create().After("gorm:create").Register("tracing:after_create", finishQuery),
+		callbacks.Query().Before("gorm:query").Register("tracing:before_query", startQuery("query")),
+		callbacks.Query().After("gorm:query").Register("tracing:after_query", finishQuery),
+		callbacks.Update().Before("gorm:update").Register("tracing:before_update", startQuery("update")),
+		callbacks.Update().After("gorm:update").Register("tracing:after_update", finishQuery),
+		callbacks.Delete().Before("gorm:delete").Register("tracing:before_delete", startQuery("delete")),
+		callbacks.Delete().After("gorm:delete").Register("tracing:after_delete", finishQuery),
+		callbacks.Row().Before("gorm:row").Register("tracing:before_row", startQuery("row")),
+		callbacks.Row().After("gorm:row").Register("tracing:after_row", finishQuery),
+		callbacks.Raw().Before("gorm:raw").Register("tracing:before_raw", startQuery("raw")),
+		callbacks.Raw().After("gorm:raw").Register("tracing:after_raw", finishQuery),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startQuery(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if !traced(ctx) {
+			return
+		}
+
+		_, span := Tracer().Start(ctx, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperationName(operation)),
+		)
+		db.InstanceSet(querySpanKey, span)
+	}
+}
+
+// finishQuery ends the statement's span. The SQL is recorded with its
+// placeholders, so parameter values never reach the exporter.
+func finishQuery(db *gorm.DB) {
+	value, ok := db.InstanceGet(querySpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+
+	span.SetAttributes(
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.RowsAffected),
+	)
+	if db.Statement.Table != "" {
+		span.SetAttributes(semconv.DBCollectionName(db.Statement.Table))
+	}
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+	span.End()
+}
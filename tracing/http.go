@@ -0,0 +1,35 @@
+package tracing
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// Middleware starts a span for every request, continuing the caller's trace
+// when the request carries a traceparent header. Probes, metrics scrapes and
+// the Swagger UI aren't traced.
+func Middleware(serviceName string) gin.HandlerFunc {
+	return otelgin.Middleware(serviceName, otelgin.WithFilter(func(r *http.Request) bool {
+		path := r.URL.Path
+		return path != "/metrics" &&
+			!strings.HasSuffix(path, "/livez") &&
+			!strings.HasSuffix(path, "/readyz") &&
+			!strings.HasPrefix(path, "/swagger/")
+	}))
+}
+
+// HTTPClient returns a client recording a span, named after peer and the
+// method, for each request made within a trace. The trace context is passed
+// on in the request headers.
+func HTTPClient(peer string) *http.Client {
+	return &http.Client{
+		Transport: otelhttp.NewTransport(http.DefaultTransport,
+			otelhttp.WithFilter(func(r *http.Request) bool { return traced(r.Context()) }),
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string { return peer + " " + r.Method }),
+		),
+	}
+}
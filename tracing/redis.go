@@ -0,0 +1,72 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RedisHook records a span for every Redis command and pipeline run within a
+// trace. Only command names are recorded, not keys or values.
+type RedisHook struct{}
+
+var _ redis.Hook = RedisHook{}
+
+func (RedisHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	if !traced(ctx) {
+		return ctx, nil
+	}
+	ctx, _ = startRedis(ctx, "redis."+cmd.Name(), attribute.String("db.operation.name", cmd.Name()))
+	return ctx, nil
+}
+
+func (RedisHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	finishRedis(ctx, cmd.Err())
+	return nil
+}
+
+func (RedisHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	if !traced(ctx) {
+		return ctx, nil
+	}
+	ctx, _ = startRedis(ctx, "redis.pipeline", attribute.Int("db.operation.batch.size", len(cmds)))
+	return ctx, nil
+}
+
+func (RedisHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if cmdErr := cmd.Err(); cmdErr != nil && !errors.Is(cmdErr, redis.Nil) {
+			err = cmdErr
+			break
+		}
+	}
+	finishRedis(ctx, err)
+	return nil
+}
+
+func startRedis(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, semconv.DBSystemRedis)...),
+	)
+}
+
+// finishRedis ends the span started for the command. A missing key isn't a
+// failure, so redis.Nil isn't recorded as an error.
+func finishRedis(ctx context.Context, err error) {
+	if !traced(ctx) {
+		return
+	}
+	span := trace.SpanFromContext(ctx)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,56 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/susbuntu/blog-api/config"
+	"github.com/susbuntu/blog-api/search"
+	"gorm.io/gorm"
+)
+
+// ConsistencyChecker periodically compares the search index with Postgres. A
+// Postgres advisory lock lets only one replica check at a time; the others
+// skip that round.
+type ConsistencyChecker struct {
+	DB       *gorm.DB
+	ES       *elastic.Client
+	Interval time.Duration
+	// Repair queues outbox events to fix the drift each check finds
+	Repair bool
+}
+
+func NewConsistencyChecker(db *gorm.DB, es *elastic.Client, cfg config.ConsistencyConfig) *ConsistencyChecker {
+	return &ConsistencyChecker{
+		DB:       db,
+		ES:       es,
+		Interval: cfg.Interval,
+		Repair:   cfg.Repair,
+	}
+}
+
+// Run checks the index every Interval until the context is cancelled. The
+// first check runs one Interval after start, so restarts don't trigger a
+// full scan each time.
+func (c *ConsistencyChecker) Run(ctx context.Context) {
+	log.Printf("Search consistency checker started (interval %s, repair %t)", c.Interval, c.Repair)
+
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		_, err := search.CheckConsistency(ctx, c.ES, c.DB, c.Repair)
+		if err != nil && !errors.Is(err, search.ErrConsistencyCheckRunning) {
+			log.Printf("Search consistency check failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/susbuntu/blog-api/config"
+	"github.com/susbuntu/blog-api/metrics"
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/outbox"
+	"github.com/susbuntu/blog-api/search"
+	"github.com/susbuntu/blog-api/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// outboxBatchSize caps how many events one replica claims per tick
+const outboxBatchSize = 100
+
+// outboxSyncTimeout bounds each search index request, so one hung call can't
+// hold the claimed events locked indefinitely
+const outboxSyncTimeout = 10 * time.Second
+
+// OutboxRelay delivers outbox events to the search index. Due events are claimed
+// with SELECT ... FOR UPDATE SKIP LOCKED, so several API replicas can run the
+// relay at once without delivering the same event twice. A failed delivery is
+// retried with exponential backoff until MaxAttempts, after which the event is
+// dead-lettered and waits for a replay.
+type OutboxRelay struct {
+	DB          *gorm.DB
+	Index       search.SearchIndex
+	Interval    time.Duration
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+func NewOutboxRelay(db *gorm.DB, index search.SearchIndex, cfg config.OutboxConfig) *OutboxRelay {
+	return &OutboxRelay{
+		DB:          db,
+		Index:       index,
+		Interval:    cfg.Interval,
+		MaxAttempts: cfg.MaxAttempts,
+		Backoff:     cfg.Backoff,
+		MaxBackoff:  cfg.MaxBackoff,
+	}
+}
+
+// Run delivers due events every Interval until the context is cancelled
+func (r *OutboxRelay) Run(ctx context.Context) {
+	log.Printf("Outbox relay started (interval %s, max attempts %d)", r.Interval, r.MaxAttempts)
+
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		// Keep draining while full batches come back so a backlog doesn't wait for the next tick
+		for {
+			claimed, err := r.DeliverDue(ctx)
+			if err != nil {
+				log.Printf("Outbox delivery failed: %v", err)
+				break
+			}
+			if len(claimed) < outboxBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue delivers one batch of due events and returns the events it claimed.
+// Delivered events are deleted; failed ones are rescheduled or dead-lettered.
+func (r *OutboxRelay) DeliverDue(ctx context.Context) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.OutboxPending, time.Now()).
+			Order("id ASC").
+			Limit(outboxBatchSize).
+			Find(&events).Error
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		// Every event syncs the post's current state, so a post with several
+		// events in the batch only needs to be synced once
+		byPost := make(map[uint][]*models.OutboxEvent)
+		var order []uint
+		for i := range events {
+			event := &events[i]
+			if _, ok := byPost[event.PostID]; !ok {
+				order = append(order, event.PostID)
+			}
+			byPost[event.PostID] = append(byPost[event.PostID], event)
+		}
+
+		var delivered []uint
+		for _, postID := range order {
+			syncErr := r.syncPost(ctx, tx, postID, byPost[postID])
+			for _, event := range byPost[postID] {
+				if syncErr == nil {
+					delivered = append(delivered, event.ID)
+					continue
+				}
+				if err := tx.Model(event).Updates(r.failure(event, syncErr)).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(delivered) == 0 {
+			return nil
+		}
+		return tx.Where("id IN ?", delivered).Delete(&models.OutboxEvent{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// syncPost makes the post's search document match Postgres: live posts are
+// indexed, and trashed or purged ones removed
+func (r *OutboxRelay) syncPost(ctx context.Context, tx *gorm.DB, postID uint, events []*models.OutboxEvent) (err error) {
+	ctx, span := traceSync(ctx, postID, events)
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	var post models.Post
+	err = tx.WithContext(ctx).First(&post, postID).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, outboxSyncTimeout)
+	defer cancel()
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if err := r.Index.DeletePost(ctx, postID); err != nil {
+			metrics.SearchSyncFailures.WithLabelValues("delete").Inc()
+			return err
+		}
+		return nil
+	}
+	if err := r.Index.IndexPost(ctx, post); err != nil {
+		metrics.SearchSyncFailures.WithLabelValues("index").Inc()
+		return err
+	}
+	return nil
+}
+
+// traceSync starts the span of syncing a post. It continues the trace of the
+// request that queued the first of the post's events and links the others, so
+// the indexing shows up under the write that caused it.
+func traceSync(ctx context.Context, postID uint, events []*models.OutboxEvent) (context.Context, trace.Span) {
+	var parent string
+	var links []trace.Link
+	for _, event := range events {
+		switch {
+		case event.TraceParent == "":
+		case parent == "":
+			parent = event.TraceParent
+		default:
+			links = append(links, trace.Link{SpanContext: tracing.SpanContext(event.TraceParent)})
+		}
+	}
+
+	return tracing.Tracer().Start(tracing.WithTraceParent(ctx, parent), "outbox.sync_post",
+		trace.WithLinks(links...),
+		trace.WithAttributes(
+			attribute.Int("post.id", int(postID)),
+			attribute.Int("outbox.events", len(events)),
+		),
+	)
+}
+
+// failure records a failed delivery attempt, dead-lettering the event once it
+// has used up its attempts
+func (r *OutboxRelay) failure(event *models.OutboxEvent, err error) map[string]interface{} {
+	attempts := event.Attempts + 1
+	updates := map[string]interface{}{
+		"attempts":   attempts,
+		"last_error": err.Error(),
+	}
+
+	if attempts >= r.MaxAttempts {
+		updates["status"] = models.OutboxDead
+		log.Printf("Outbox event %d for post %d dead-lettered after %d attempts: %v", event.ID, event.PostID, attempts, err)
+		return updates
+	}
+
+	delay := outbox.Backoff(attempts, r.Backoff, r.MaxBackoff)
+	updates["next_attempt_at"] = time.Now().Add(delay)
+	log.Printf("Failed to sync post %d to Elasticsearch (attempt %d, retrying in %s): %v", event.PostID, attempts, delay, err)
+	return updates
+}
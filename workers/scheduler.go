@@ -0,0 +1,107 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/susbuntu/blog-api/cache"
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/outbox"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// schedulerBatchSize caps how many posts one replica claims per tick
+const schedulerBatchSize = 50
+
+// Scheduler publishes drafts whose scheduled_for time has passed. Due posts are
+// claimed with SELECT ... FOR UPDATE SKIP LOCKED, so several API replicas can run
+// the scheduler at once without publishing the same post twice.
+type Scheduler struct {
+	DB       *gorm.DB
+	Cache    cache.Cache
+	Interval time.Duration
+}
+
+func NewScheduler(db *gorm.DB, c cache.Cache, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		DB:       db,
+		Cache:    c,
+		Interval: interval,
+	}
+}
+
+// Run publishes due posts every Interval until the context is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	log.Printf("Scheduled publishing worker started (interval %s)", s.Interval)
+
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		// Keep draining while full batches come back so a backlog doesn't wait for the next tick
+		for {
+			published, err := s.PublishDue(ctx)
+			if err != nil {
+				log.Printf("Scheduled publishing failed: %v", err)
+				break
+			}
+			if len(published) < schedulerBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishDue publishes one batch of due posts and returns them
+func (s *Scheduler) PublishDue(ctx context.Context) ([]models.Post, error) {
+	var posts []models.Post
+
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND scheduled_for <= ?", models.PostDraft, time.Now()).
+			Order("scheduled_for ASC").
+			Limit(schedulerBatchSize).
+			Find(&posts).Error
+		if err != nil || len(posts) == 0 {
+			return err
+		}
+
+		logs := make([]models.ActivityLog, 0, len(posts))
+		ids := make([]uint, 0, len(posts))
+		for i := range posts {
+			post := &posts[i]
+			post.Status = models.PostPublished
+			post.PublishedAt = post.ScheduledFor
+			post.ScheduledFor = nil
+
+			if err := tx.Model(post).Select("status", "published_at", "scheduled_for").Updates(post).Error; err != nil {
+				return err
+			}
+			logs = append(logs, models.ActivityLog{Action: "scheduled_publish", PostID: &post.ID})
+			ids = append(ids, post.ID)
+		}
+		if err := tx.Create(&logs).Error; err != nil {
+			return err
+		}
+		return outbox.IndexPosts(tx, ids)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, post := range posts {
+		s.Cache.Delete(ctx, cache.PostKey(post.ID))
+		s.Cache.Delete(ctx, cache.FeedKeys(post.Tags...)...)
+		log.Printf("Published scheduled post %d", post.ID)
+	}
+
+	return posts, nil
+}
@@ -0,0 +1,206 @@
+package workers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/susbuntu/blog-api/cache"
+	"github.com/susbuntu/blog-api/config"
+	"github.com/susbuntu/blog-api/models"
+	"github.com/susbuntu/blog-api/storage"
+	"github.com/susbuntu/blog-api/thumbnail"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// thumbnailBatchSize caps how many uploads one replica claims per tick. Images
+// are resized while their rows are locked, so batches stay small.
+const thumbnailBatchSize = 10
+
+// Thumbnailer generates resized variants of uploaded images. Pending media are
+// claimed with SELECT ... FOR UPDATE SKIP LOCKED, so several API replicas can
+// run it at once without resizing the same image twice.
+type Thumbnailer struct {
+	DB          *gorm.DB
+	Cache       cache.Cache
+	Blobs       storage.BlobStore
+	Interval    time.Duration
+	Widths      []int
+	Formats     []string
+	JPEGQuality int
+	MaxPixels   int
+}
+
+func NewThumbnailer(db *gorm.DB, c cache.Cache, blobs storage.BlobStore, cfg config.ThumbnailsConfig) *Thumbnailer {
+	widths := append([]int(nil), cfg.Widths...)
+	sort.Ints(widths)
+
+	return &Thumbnailer{
+		DB:          db,
+		Cache:       c,
+		Blobs:       blobs,
+		Interval:    cfg.Interval,
+		Widths:      widths,
+		Formats:     cfg.Formats,
+		JPEGQuality: cfg.JPEGQuality,
+		MaxPixels:   cfg.MaxPixels,
+	}
+}
+
+// Run processes pending uploads every Interval until the context is cancelled
+func (t *Thumbnailer) Run(ctx context.Context) {
+	log.Printf("Thumbnail worker started (interval %s, widths %v, formats %v)", t.Interval, t.Widths, t.Formats)
+
+	ticker := time.NewTicker(t.Interval)
+	defer ticker.Stop()
+
+	for {
+		// Keep draining while full batches come back so a backlog doesn't wait for the next tick
+		for {
+			processed, err := t.ProcessPending(ctx)
+			if err != nil {
+				log.Printf("Thumbnail generation failed: %v", err)
+				break
+			}
+			if len(processed) < thumbnailBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessPending generates variants for one batch of pending uploads and returns them
+func (t *Thumbnailer) ProcessPending(ctx context.Context) ([]models.Media, error) {
+	var media []models.Media
+
+	err := t.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("thumbnails = ?", models.ThumbnailsPending).
+			Order("id ASC").
+			Limit(thumbnailBatchSize).
+			Find(&media).Error
+		if err != nil || len(media) == 0 {
+			return err
+		}
+
+		for i := range media {
+			item := &media[i]
+			if err := t.generate(ctx, item); err != nil {
+				log.Printf("Failed to generate thumbnails for media %d: %v", item.ID, err)
+				item.Thumbnails = models.ThumbnailsFailed
+				item.Variants = nil
+			}
+
+			if err := tx.Model(item).Select("width", "height", "thumbnails", "variants").Updates(item).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(media) == 0 {
+		return media, nil
+	}
+
+	// Cached posts embed their media, so drop the posts these uploads are attached to
+	ids := make([]uint, len(media))
+	for i, item := range media {
+		ids[i] = item.ID
+	}
+	var postIDs []uint
+	if err := t.DB.WithContext(ctx).Model(&models.PostMedia{}).Where("media_id IN ?", ids).Distinct().Pluck("post_id", &postIDs).Error; err != nil {
+		log.Printf("Failed to find posts using processed media: %v", err)
+	}
+	for _, postID := range postIDs {
+		t.Cache.Delete(ctx, cache.PostKey(postID))
+	}
+
+	return media, nil
+}
+
+// generate decodes an upload and stores a variant for every configured width
+// narrower than the original, in every configured format. If it fails part way,
+// the variants it already stored are deleted again.
+func (t *Thumbnailer) generate(ctx context.Context, media *models.Media) (err error) {
+	if !thumbnail.Resizable[media.MIMEType] {
+		media.Thumbnails = models.ThumbnailsSkipped
+		return nil
+	}
+
+	original, err := t.Blobs.Open(ctx, media.StorageKey)
+	if err != nil {
+		return err
+	}
+	img, err := thumbnail.Decode(original, t.MaxPixels)
+	original.Close()
+	if err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+
+	var stored []string
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, key := range stored {
+			if delErr := t.Blobs.Delete(context.Background(), key); delErr != nil {
+				log.Printf("Failed to delete thumbnail %s: %v", key, delErr)
+			}
+		}
+	}()
+
+	bounds := img.Bounds()
+	media.Width = bounds.Dx()
+	media.Height = bounds.Dy()
+	media.Variants = models.MediaVariants{}
+
+	for _, width := range t.Widths {
+		if width >= media.Width {
+			break
+		}
+		resized := thumbnail.Resize(img, width)
+
+		for _, format := range t.Formats {
+			mimeType := thumbnail.MIMEType(format)
+			if mimeType == "" {
+				continue
+			}
+
+			var buf bytes.Buffer
+			if err := thumbnail.Encode(&buf, resized, format, t.JPEGQuality); err != nil {
+				return fmt.Errorf("encode %s: %w", format, err)
+			}
+
+			name := thumbnail.VariantName(width, format)
+			size := int64(buf.Len())
+			key := thumbnail.VariantKey(media.StorageKey, name)
+			if err := t.Blobs.Put(ctx, key, &buf); err != nil {
+				return fmt.Errorf("store %s: %w", name, err)
+			}
+			stored = append(stored, key)
+
+			media.Variants = append(media.Variants, models.MediaVariant{
+				Name:     name,
+				Width:    width,
+				Height:   resized.Bounds().Dy(),
+				MIMEType: mimeType,
+				Size:     size,
+			})
+		}
+	}
+
+	media.Thumbnails = models.ThumbnailsReady
+	return nil
+}